@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/moderation"
+	"github.com/google/uuid"
+)
+
+// loadBannedWords builds the word list from a file when one is configured,
+// otherwise from the banned_words table.
+func loadBannedWords(ctx context.Context, dbQueries *database.Queries, path string) (*moderation.WordList, error) {
+
+	if path != "" {
+		entries, err := moderation.LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return moderation.NewWordList(entries)
+	}
+
+	rows, err := dbQueries.GetBannedWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]moderation.Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, moderation.Entry{Word: row.Word, Action: moderation.Action(row.Action)})
+	}
+
+	return moderation.NewWordList(entries)
+}
+
+// flagChirp queues a chirp for review, once per flagged word it contains.
+func flagChirp(ctx context.Context, q *database.Queries, chirpID uuid.UUID, result moderation.Result) error {
+	for _, match := range result.Matches {
+		if match.Action != moderation.ActionFlag {
+			continue
+		}
+
+		sendData := database.CreateChirpFlagParams{
+			ChirpID: chirpID,
+			Word:    match.Word,
+		}
+
+		if err := q.CreateChirpFlag(ctx, sendData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type bannedWord struct {
+	Word   string `json:"word"`
+	Action string `json:"action"`
+}
+
+func (cfg *apiConfig) handlerGetBannedWords(w http.ResponseWriter, r *http.Request) {
+
+	entries := cfg.bannedWords.Entries()
+
+	words := make([]bannedWord, 0, len(entries))
+	for _, entry := range entries {
+		words = append(words, bannedWord{Word: entry.Word, Action: string(entry.Action)})
+	}
+
+	sendBack := struct {
+		Words []bannedWord `json:"words"`
+	}{
+		words,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
+
+func (cfg *apiConfig) handlerPutBannedWord(w http.ResponseWriter, r *http.Request) {
+
+	type recieve struct {
+		Action string `json:"action"`
+	}
+
+	requestData := recieve{}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		respondWithError(w, 400, "Couldn't decode parameters", err)
+		return
+	}
+
+	action, err := moderation.ParseAction(requestData.Action)
+	if err != nil {
+		respondWithError(w, 400, "Action must be mask, reject or flag", err)
+		return
+	}
+
+	entry, err := moderation.NewEntry(r.PathValue("word"), action)
+	if err != nil {
+		respondWithError(w, 400, "Invalid banned word", err)
+		return
+	}
+
+	// Save first so the live list never holds a word the table doesn't
+	if cfg.persistBannedWords {
+		sendData := database.UpsertBannedWordParams{
+			Word:   entry.Word,
+			Action: string(entry.Action),
+		}
+
+		if err := cfg.dbQueries.UpsertBannedWord(r.Context(), sendData); err != nil {
+			respondWithError(w, 500, "Couldn't save banned word", err)
+			return
+		}
+	}
+
+	if _, err := cfg.bannedWords.Set(entry.Word, entry.Action); err != nil {
+		respondWithError(w, 500, "Couldn't update banned words", err)
+		return
+	}
+
+	respondWithJSON(w, 200, bannedWord{Word: entry.Word, Action: string(entry.Action)})
+}
+
+func (cfg *apiConfig) handlerDeleteBannedWord(w http.ResponseWriter, r *http.Request) {
+
+	entry, ok := cfg.bannedWords.Lookup(r.PathValue("word"))
+	if !ok {
+		respondWithError(w, 404, "Banned word not found", nil)
+		return
+	}
+
+	// Rows are stored under the normalized word, not whatever case the
+	// client used in the path
+	if cfg.persistBannedWords {
+		if err := cfg.dbQueries.DeleteBannedWord(r.Context(), entry.Word); err != nil {
+			respondWithError(w, 500, "Couldn't delete banned word", err)
+			return
+		}
+	}
+
+	cfg.bannedWords.Remove(entry.Word)
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerGetChirpFlags(w http.ResponseWriter, r *http.Request) {
+
+	dbFlags, err := cfg.dbQueries.GetChirpFlags(r.Context())
+	if err != nil {
+		respondWithError(w, 500, "Couldn't get chirp flags", err)
+		return
+	}
+
+	type flag struct {
+		ChirpID   uuid.UUID `json:"chirp_id"`
+		Word      string    `json:"word"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	flags := make([]flag, 0, len(dbFlags))
+	for _, f := range dbFlags {
+		flags = append(flags, flag{ChirpID: f.ChirpID, Word: f.Word, CreatedAt: f.CreatedAt})
+	}
+
+	sendBack := struct {
+		Flags []flag `json:"flags"`
+	}{
+		flags,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+)
+
+// viewerID returns the caller's user ID when the request carries a valid
+// access token. Anonymous callers get an invalid NullUUID.
+func (cfg *apiConfig) viewerID(r *http.Request) uuid.NullUUID {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		return uuid.NullUUID{}
+	}
+
+	id, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		return uuid.NullUUID{}
+	}
+
+	return uuid.NullUUID{UUID: id, Valid: true}
+}
+
+// addLikeStats fills in like_count and liked_by_me for a batch of chirps
+// with a single query.
+func (cfg *apiConfig) addLikeStats(ctx context.Context, chirps []*Chirp, viewer uuid.NullUUID) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		ids = append(ids, chirp.ID)
+	}
+
+	stats, err := cfg.dbQueries.GetChirpLikeStats(ctx, database.GetChirpLikeStatsParams{
+		ViewerID: viewer,
+		ChirpIds: ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	byChirp := make(map[uuid.UUID]database.GetChirpLikeStatsRow, len(stats))
+	for _, stat := range stats {
+		byChirp[stat.ChirpID] = stat
+	}
+
+	for _, chirp := range chirps {
+		stat := byChirp[chirp.ID]
+		chirp.LikeCount = stat.LikeCount
+		chirp.LikedByMe = stat.LikedByMe
+	}
+
+	return nil
+}
+
+func chirpRefs(chirps []Chirp) []*Chirp {
+	refs := make([]*Chirp, 0, len(chirps))
+	for i := range chirps {
+		refs = append(refs, &chirps[i])
+	}
+	return refs
+}
+
+func (cfg *apiConfig) handlerLikeChirp(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	if _, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID); err != nil {
+		respondWithDBError(w, "Chirp not found", err)
+		return
+	}
+
+	sendData := database.LikeChirpParams{
+		ChirpID: chirpID,
+		UserID:  userID,
+	}
+
+	if err := cfg.dbQueries.LikeChirp(r.Context(), sendData); err != nil {
+		respondWithError(w, 500, "Couldn't like chirp", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUnlikeChirp(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	sendData := database.UnlikeChirpParams{
+		ChirpID: chirpID,
+		UserID:  userID,
+	}
+
+	if err := cfg.dbQueries.UnlikeChirp(r.Context(), sendData); err != nil {
+		respondWithError(w, 500, "Couldn't unlike chirp", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// purgeDeletedChirps permanently removes soft-deleted chirps once they are
+// older than the retention period. It runs for the lifetime of the server.
+func (cfg *apiConfig) purgeDeletedChirps(retention, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := cfg.dbQueries.PurgeDeletedChirps(context.Background(), int64(retention.Seconds()))
+		if err != nil {
+			log.Printf("Error purging deleted chirps: %s", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d deleted chirps", purged)
+		}
+
+		<-ticker.C
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerRestoreChirp(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	chirp, err := cfg.dbQueries.GetDeletedChirpByID(r.Context(), chirpID)
+	if err != nil {
+		respondWithDBError(w, "Deleted chirp not found", err)
+		return
+	}
+
+	if chirp.UserID != userID {
+		respondWithError(w, 403, "You can only restore your own chirps", nil)
+		return
+	}
+
+	chirp, err = cfg.dbQueries.RestoreChirp(r.Context(), chirp.ID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't restore chirp", err)
+		return
+	}
+
+	sendBack := databaseChirpToChirp(chirp)
+
+	if err := cfg.addLikeStats(r.Context(), []*Chirp{&sendBack}, uuid.NullUUID{UUID: userID, Valid: true}); err != nil {
+		respondWithError(w, 500, "Couldn't get like counts", err)
+		return
+	}
+
+	if err := cfg.addMedia(r.Context(), []*Chirp{&sendBack}); err != nil {
+		respondWithError(w, 500, "Couldn't get chirp media", err)
+		return
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerUpdateChirp(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	type recieve struct {
+		Body string `json:"body"`
+	}
+
+	requestData := recieve{}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		respondWithError(w, 400, "Couldn't decode parameters", err)
+		return
+	}
+
+	// Lock the chirp for the whole edit so two concurrent edits can't both
+	// record the same previous body as their revision.
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't start transaction", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	chirp, err := qtx.GetChirpByIDForUpdate(r.Context(), chirpID)
+	if err != nil {
+		respondWithDBError(w, "Chirp not found", err)
+		return
+	}
+
+	if chirp.UserID != userID {
+		respondWithError(w, 403, "You can only edit your own chirps", nil)
+		return
+	}
+
+	blocked, err := cfg.checkCanPost(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't find user", err)
+		return
+	}
+
+	if blocked != "" {
+		respondWithError(w, 403, blocked, nil)
+		return
+	}
+
+	if errs := validateChirpBody(requestData.Body); errs != nil {
+		respondWithErrorDetails(w, 400, "Invalid chirp", nil, errs)
+		return
+	}
+
+	moderated, err := cfg.moderateChirpBody(requestData.Body)
+	if err != nil {
+		respondWithError(w, 400, err.Error(), nil)
+		return
+	}
+
+	// Only keep a revision when the text actually changes
+	if moderated.Text != chirp.Body {
+
+		revision := database.CreateChirpRevisionParams{
+			ChirpID:   chirp.ID,
+			Body:      chirp.Body,
+			CreatedAt: chirp.UpdatedAt,
+		}
+
+		if err := qtx.CreateChirpRevision(r.Context(), revision); err != nil {
+			respondWithError(w, 500, "Couldn't save chirp revision", err)
+			return
+		}
+
+		sendData := database.UpdateChirpBodyParams{
+			ID:   chirp.ID,
+			Body: moderated.Text,
+		}
+
+		chirp, err = qtx.UpdateChirpBody(r.Context(), sendData)
+		if err != nil {
+			respondWithError(w, 500, "Couldn't update chirp", err)
+			return
+		}
+
+		if err := replaceMentions(r.Context(), qtx, chirp); err != nil {
+			respondWithError(w, 500, "Couldn't save mentions", err)
+			return
+		}
+
+		if err := replaceHashtags(r.Context(), qtx, chirp); err != nil {
+			respondWithError(w, 500, "Couldn't save hashtags", err)
+			return
+		}
+	}
+
+	if err := flagChirp(r.Context(), qtx, chirp.ID, moderated); err != nil {
+		respondWithError(w, 500, "Couldn't flag chirp", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, 500, "Couldn't commit chirp update", err)
+		return
+	}
+
+	sendBack := databaseChirpToChirp(chirp)
+
+	if err := cfg.addLikeStats(r.Context(), []*Chirp{&sendBack}, uuid.NullUUID{UUID: userID, Valid: true}); err != nil {
+		respondWithError(w, 500, "Couldn't get like counts", err)
+		return
+	}
+
+	if err := cfg.addMedia(r.Context(), []*Chirp{&sendBack}); err != nil {
+		respondWithError(w, 500, "Couldn't get chirp media", err)
+		return
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
+
+func (cfg *apiConfig) handlerGetChirpRevisions(w http.ResponseWriter, r *http.Request) {
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	if _, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID); err != nil {
+		respondWithDBError(w, "Chirp not found", err)
+		return
+	}
+
+	dbRevisions, err := cfg.dbQueries.GetChirpRevisions(r.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't get chirp revisions", err)
+		return
+	}
+
+	type revision struct {
+		ID         uuid.UUID `json:"id"`
+		Body       string    `json:"body"`
+		CreatedAt  time.Time `json:"created_at"`
+		ReplacedAt time.Time `json:"replaced_at"`
+	}
+
+	revisions := make([]revision, 0, len(dbRevisions))
+	for _, rev := range dbRevisions {
+		revisions = append(revisions, revision{
+			ID:         rev.ID,
+			Body:       rev.Body,
+			CreatedAt:  rev.CreatedAt,
+			ReplacedAt: rev.ReplacedAt,
+		})
+	}
+
+	sendBack := struct {
+		ChirpID   uuid.UUID  `json:"chirp_id"`
+		Revisions []revision `json:"revisions"`
+	}{
+		chirpID,
+		revisions,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/pagination"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerSearchChirps(w http.ResponseWriter, r *http.Request) {
+
+	searchQuery := strings.TrimSpace(r.URL.Query().Get("q"))
+	authorID := r.URL.Query().Get("author_id")
+
+	if searchQuery == "" {
+		respondWithError(w, 400, "Missing search query", nil)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid limit", err)
+		return
+	}
+
+	offset, err := pagination.ParseOffset(r.URL.Query().Get("offset"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid offset", err)
+		return
+	}
+
+	author := uuid.NullUUID{}
+	if authorID != "" {
+
+		id, err := uuid.Parse(authorID)
+		if err != nil {
+			respondWithError(w, 400, "Invalid author ID", err)
+			return
+		}
+
+		author = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	sendData := database.SearchChirpsParams{
+		SearchQuery: searchQuery,
+		AuthorID:    author,
+		PageLimit:   int32(limit),
+		PageOffset:  int32(offset),
+	}
+
+	rows, err := cfg.dbQueries.SearchChirps(r.Context(), sendData)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't search chirps", err)
+		return
+	}
+
+	type result struct {
+		Chirp
+		Rank float32 `json:"rank"`
+	}
+
+	results := make([]result, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, result{
+			Chirp: Chirp{
+				ID:            row.ID,
+				CreatedAt:     row.CreatedAt,
+				UpdatedAt:     row.UpdatedAt,
+				Body:          row.Body,
+				UserID:        row.UserID,
+				ParentID:      row.ParentID,
+				QuotedChirpID: row.QuotedChirpID,
+			},
+			Rank: row.Rank,
+		})
+	}
+
+	refs := make([]*Chirp, 0, len(results))
+	for i := range results {
+		refs = append(refs, &results[i].Chirp)
+	}
+
+	if err := cfg.addLikeStats(r.Context(), refs, cfg.viewerID(r)); err != nil {
+		respondWithError(w, 500, "Couldn't get like counts", err)
+		return
+	}
+
+	if err := cfg.addMedia(r.Context(), refs); err != nil {
+		respondWithError(w, 500, "Couldn't get chirp media", err)
+		return
+	}
+
+	if embedsAuthor(r) {
+		if err := cfg.addAuthors(r.Context(), refs); err != nil {
+			respondWithError(w, 500, "Couldn't get chirp authors", err)
+			return
+		}
+	}
+
+	sendBack := struct {
+		Chirps []result `json:"chirps"`
+	}{
+		results,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
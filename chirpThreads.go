@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+)
+
+type threadNode struct {
+	Chirp
+	Deleted    bool          `json:"deleted,omitempty"`
+	Hidden     bool          `json:"hidden,omitempty"`
+	Depth      int32         `json:"depth"`
+	ReplyCount int64         `json:"reply_count"`
+	Replies    []*threadNode `json:"replies"`
+}
+
+// threadPlaceholder is how a deleted or hidden chirp appears in a thread:
+// enough to keep its replies in place, but nothing about who wrote it or when.
+type threadPlaceholder struct {
+	ID         uuid.UUID     `json:"id"`
+	ParentID   uuid.NullUUID `json:"parent_id"`
+	Deleted    bool          `json:"deleted,omitempty"`
+	Hidden     bool          `json:"hidden,omitempty"`
+	Depth      int32         `json:"depth"`
+	ReplyCount int64         `json:"reply_count"`
+	Replies    []*threadNode `json:"replies"`
+}
+
+func (n *threadNode) MarshalJSON() ([]byte, error) {
+	if !n.Deleted && !n.Hidden {
+		type plain threadNode
+		return json.Marshal((*plain)(n))
+	}
+
+	return json.Marshal(threadPlaceholder{
+		ID:         n.ID,
+		ParentID:   n.ParentID,
+		Deleted:    n.Deleted,
+		Hidden:     n.Hidden,
+		Depth:      n.Depth,
+		ReplyCount: n.ReplyCount,
+		Replies:    n.Replies,
+	})
+}
+
+// buildThread turns the rows of GetChirpThread into a tree. Rows arrive
+// ordered by depth, so every parent is seen before its replies. Deleted and
+// hidden chirps stay in the tree as placeholders so their replies keep their
+// place in the conversation. visible holds every chirp that isn't one.
+func buildThread(rows []database.GetChirpThreadRow) (root *threadNode, visible []*Chirp) {
+	nodes := make(map[uuid.UUID]*threadNode, len(rows))
+	visible = make([]*Chirp, 0, len(rows))
+
+	for _, row := range rows {
+		node := &threadNode{
+			Chirp: Chirp{
+				ID:       row.ID,
+				ParentID: row.ParentID,
+			},
+			Deleted:    row.DeletedAt.Valid,
+			Hidden:     row.HiddenAt.Valid,
+			Depth:      row.Depth,
+			ReplyCount: row.ReplyCount,
+			Replies:    []*threadNode{},
+		}
+		nodes[row.ID] = node
+
+		if !node.Deleted && !node.Hidden {
+			node.CreatedAt = row.CreatedAt
+			node.UpdatedAt = row.UpdatedAt
+			node.Body = row.Body
+			node.UserID = row.UserID
+			node.QuotedChirpID = row.QuotedChirpID
+			visible = append(visible, &node.Chirp)
+		}
+
+		if row.Depth == 0 {
+			root = node
+			continue
+		}
+
+		if parent, ok := nodes[row.ParentID.UUID]; ok {
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+
+	return root, visible
+}
+
+func (cfg *apiConfig) handlerGetChirpThread(w http.ResponseWriter, r *http.Request) {
+	uuidString := r.PathValue("chirpID")
+
+	id, err := uuid.Parse(uuidString)
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	if _, err := cfg.dbQueries.GetChirpByID(r.Context(), id); err != nil {
+		respondWithDBError(w, "Chirp not found", err)
+		return
+	}
+
+	rows, err := cfg.dbQueries.GetChirpThread(r.Context(), id)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't get thread", err)
+		return
+	}
+
+	if len(rows) == 0 {
+		respondWithError(w, 404, "Chirp not found", nil)
+		return
+	}
+
+	root, visible := buildThread(rows)
+
+	if err := cfg.addLikeStats(r.Context(), visible, cfg.viewerID(r)); err != nil {
+		respondWithError(w, 500, "Couldn't get like counts", err)
+		return
+	}
+
+	if err := cfg.addMedia(r.Context(), visible); err != nil {
+		respondWithError(w, 500, "Couldn't get chirp media", err)
+		return
+	}
+
+	if embedsAuthor(r) {
+		if err := cfg.addAuthors(r.Context(), visible); err != nil {
+			respondWithError(w, 500, "Couldn't get chirp authors", err)
+			return
+		}
+	}
+
+	respondWithJSON(w, 200, root)
+}
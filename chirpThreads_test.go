@@ -0,0 +1,85 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestBuildThreadPlaceholders(t *testing.T) {
+	now := time.Now().UTC()
+	gone := sql.NullTime{Time: now, Valid: true}
+
+	rootID, deletedID, hiddenID, replyID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	author := uuid.New()
+
+	rows := []database.GetChirpThreadRow{
+		{ID: rootID, CreatedAt: now, UpdatedAt: now, Body: "root", UserID: author, Depth: 0, ReplyCount: 2},
+		{ID: deletedID, CreatedAt: now, UpdatedAt: now, Body: "deleted", UserID: author, ParentID: uuid.NullUUID{UUID: rootID, Valid: true}, DeletedAt: gone, Depth: 1, ReplyCount: 1},
+		{ID: hiddenID, CreatedAt: now, UpdatedAt: now, Body: "hidden", UserID: author, ParentID: uuid.NullUUID{UUID: rootID, Valid: true}, HiddenAt: gone, Depth: 1},
+		{ID: replyID, CreatedAt: now, UpdatedAt: now, Body: "reply", UserID: author, ParentID: uuid.NullUUID{UUID: deletedID, Valid: true}, Depth: 2},
+	}
+
+	root, visible := buildThread(rows)
+	if root == nil || root.ID != rootID {
+		t.Fatalf("root = %v, want %v", root, rootID)
+	}
+
+	if len(visible) != 2 {
+		t.Errorf("len(visible) = %d, want 2", len(visible))
+	}
+	for _, chirp := range visible {
+		if chirp.ID == deletedID || chirp.ID == hiddenID {
+			t.Errorf("placeholder %v should not be visible", chirp.ID)
+		}
+	}
+
+	data, err := json.Marshal(root)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got["user_id"] != author.String() || got["body"] != "root" {
+		t.Errorf("root = %v, want its author and body", got)
+	}
+
+	replies := got["replies"].([]interface{})
+	if len(replies) != 2 {
+		t.Fatalf("len(replies) = %d, want 2", len(replies))
+	}
+
+	for _, reply := range replies {
+		node := reply.(map[string]interface{})
+		for _, field := range []string{"user_id", "body", "created_at", "updated_at", "author", "like_count"} {
+			if _, ok := node[field]; ok {
+				t.Errorf("placeholder %v has %q", node["id"], field)
+			}
+		}
+	}
+
+	deleted := replies[0].(map[string]interface{})
+	if deleted["deleted"] != true {
+		t.Errorf("deleted = %v, want true", deleted["deleted"])
+	}
+
+	nested := deleted["replies"].([]interface{})
+	if len(nested) != 1 {
+		t.Fatalf("len(nested replies) = %d, want 1", len(nested))
+	}
+	if child := nested[0].(map[string]interface{}); child["body"] != "reply" || child["user_id"] != author.String() {
+		t.Errorf("reply under placeholder = %v, want its author and body", child)
+	}
+
+	if hidden := replies[1].(map[string]interface{}); hidden["hidden"] != true {
+		t.Errorf("hidden = %v, want true", hidden["hidden"])
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/mailer"
+	"github.com/google/uuid"
+)
+
+const emailVerificationTTL = 24 * time.Hour
+
+// sendVerificationEmail issues a fresh token for email and mails it. Only the
+// hash is stored, so the token in the email is the only usable copy.
+func (cfg *apiConfig) sendVerificationEmail(ctx context.Context, userID uuid.UUID, email string) error {
+
+	token, err := auth.MakeSignedToken(cfg.emailTokenSecret)
+	if err != nil {
+		return err
+	}
+
+	sendData := database.CreateEmailVerificationParams{
+		TokenHash: auth.HashToken(token),
+		UserID:    userID,
+		Email:     email,
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	}
+
+	if err := cfg.dbQueries.CreateEmailVerification(ctx, sendData); err != nil {
+		return fmt.Errorf("error saving verification token: %s", err)
+	}
+
+	msg := mailer.Message{
+		To:      email,
+		Subject: "Verify your Chirpy email address",
+		Body: fmt.Sprintf("Welcome to Chirpy!\n\nUse this token to verify your email address:\n\n%s\n\nIt expires in %s.\n",
+			token, emailVerificationTTL),
+	}
+
+	return cfg.mailer.Send(ctx, msg)
+}
+
+func (cfg *apiConfig) handlerVerifyEmail(w http.ResponseWriter, r *http.Request) {
+
+	type recieve struct {
+		Token string `json:"token"`
+	}
+
+	requestData := recieve{}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		respondWithError(w, 400, "Couldn't decode parameters", err)
+		return
+	}
+
+	if err := auth.VerifySignedToken(requestData.Token, cfg.emailTokenSecret); err != nil {
+		respondWithError(w, 400, "Invalid or expired token", err)
+		return
+	}
+
+	// Marking the token used up front makes it single-use even under
+	// concurrent requests
+	verification, err := cfg.dbQueries.UseEmailVerification(r.Context(), auth.HashToken(requestData.Token))
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, 400, "Invalid or expired token", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, 500, "Couldn't verify token", err)
+		return
+	}
+
+	sendData := database.VerifyUserEmailParams{
+		ID:    verification.UserID,
+		Email: verification.Email,
+	}
+
+	rows, err := cfg.dbQueries.VerifyUserEmail(r.Context(), sendData)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't verify email", err)
+		return
+	}
+
+	// The user changed their email after the token was sent
+	if rows == 0 {
+		respondWithError(w, 400, "Invalid or expired token", nil)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerResendVerification(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithDBError(w, "User not found", err)
+		return
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		respondWithError(w, 409, "Email address is already verified", nil)
+		return
+	}
+
+	if err := cfg.sendVerificationEmail(r.Context(), user.ID, user.Email); err != nil {
+		respondWithError(w, 500, "Couldn't send verification email", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
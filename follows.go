@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/pagination"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerFollow(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	followerID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	followeeID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, 404, "User not found", err)
+		return
+	}
+
+	if followeeID == followerID {
+		respondWithError(w, 400, "You can't follow yourself", nil)
+		return
+	}
+
+	if _, err := cfg.dbQueries.GetUserByID(r.Context(), followeeID); err != nil {
+		respondWithDBError(w, "User not found", err)
+		return
+	}
+
+	sendData := database.FollowUserParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	}
+
+	if err := cfg.dbQueries.FollowUser(r.Context(), sendData); err != nil {
+		respondWithError(w, 500, "Couldn't follow user", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUnfollow(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	followerID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	followeeID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, 404, "User not found", err)
+		return
+	}
+
+	sendData := database.UnfollowUserParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	}
+
+	if err := cfg.dbQueries.UnfollowUser(r.Context(), sendData); err != nil {
+		respondWithError(w, 500, "Couldn't unfollow user", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerFeed(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	id, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid limit", err)
+		return
+	}
+
+	cursorCreatedAt, cursorID, err := parseCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid cursor", err)
+		return
+	}
+
+	sendData := database.GetFeedPageParams{
+		FollowerID:      id,
+		CursorCreatedAt: cursorCreatedAt,
+		CursorID:        cursorID,
+		PageLimit:       int32(limit + 1),
+	}
+
+	dbChirps, err := cfg.dbQueries.GetFeedPage(r.Context(), sendData)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't get feed", err)
+		return
+	}
+
+	sendBack := newChirpsPage(databaseChirpsToChirps(dbChirps), limit)
+
+	if err := cfg.addLikeStats(r.Context(), chirpRefs(sendBack.Chirps), uuid.NullUUID{UUID: id, Valid: true}); err != nil {
+		respondWithError(w, 500, "Couldn't get like counts", err)
+		return
+	}
+
+	if err := cfg.addMedia(r.Context(), chirpRefs(sendBack.Chirps)); err != nil {
+		respondWithError(w, 500, "Couldn't get chirp media", err)
+		return
+	}
+
+	if embedsAuthor(r) {
+		if err := cfg.addAuthors(r.Context(), chirpRefs(sendBack.Chirps)); err != nil {
+			respondWithError(w, 500, "Couldn't get chirp authors", err)
+			return
+		}
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/hashtags"
+	"github.com/IsahiRea/chirp/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// saveHashtags records the tags in a new chirp, creating any tag seen for
+// the first time.
+func saveHashtags(ctx context.Context, q *database.Queries, chirp database.Chirp) error {
+
+	tags := hashtags.Parse(chirp.Body)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if err := q.CreateHashtags(ctx, tags); err != nil {
+		return err
+	}
+
+	sendData := database.CreateChirpHashtagsParams{
+		ChirpID: chirp.ID,
+		Tags:    tags,
+	}
+
+	return q.CreateChirpHashtags(ctx, sendData)
+}
+
+// replaceHashtags brings an edited chirp's tags in line with its new body.
+// Tags it still has keep their rows, so an edit doesn't count them as
+// trending again.
+func replaceHashtags(ctx context.Context, q *database.Queries, chirp database.Chirp) error {
+
+	sendData := database.DeleteChirpHashtagsParams{
+		ChirpID:  chirp.ID,
+		KeepTags: hashtags.Parse(chirp.Body),
+	}
+
+	if err := q.DeleteChirpHashtags(ctx, sendData); err != nil {
+		return err
+	}
+
+	return saveHashtags(ctx, q, chirp)
+}
+
+// getChirpsByTagPage is the tagged counterpart of getChirpsPage. Filtering
+// by author here only returns the author's own chirps, not their rechirps.
+func (cfg *apiConfig) getChirpsByTagPage(ctx context.Context, tag string, authorID uuid.NullUUID, sortBy string, cursorCreatedAt sql.NullTime, cursorID uuid.NullUUID, limit int32) ([]Chirp, error) {
+
+	var dbChirps []database.Chirp
+	var err error
+
+	if sortBy == "desc" {
+		dbChirps, err = cfg.dbQueries.GetChirpsByTagPageDesc(ctx, database.GetChirpsByTagPageDescParams{
+			Tag:             tag,
+			UserID:          authorID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			PageLimit:       limit,
+		})
+	} else {
+		dbChirps, err = cfg.dbQueries.GetChirpsByTagPageAsc(ctx, database.GetChirpsByTagPageAscParams{
+			Tag:             tag,
+			UserID:          authorID,
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			PageLimit:       limit,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return databaseChirpsToChirps(dbChirps), nil
+}
+
+//--------------------------------------------------------------------------------
+
+// maxTrendingTags is how many tags the cache keeps, which is also the
+// largest page GET /api/trending can return.
+const maxTrendingTags = pagination.MaxLimit
+
+type TrendingTag struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// trendingCache holds the last computed trending list. Counting tags over
+// the whole window is too expensive to do on every request, so a background
+// loop refreshes it instead.
+type trendingCache struct {
+	mu        sync.RWMutex
+	window    time.Duration
+	tags      []TrendingTag
+	updatedAt time.Time
+}
+
+func newTrendingCache(window time.Duration) *trendingCache {
+	return &trendingCache{
+		window: window,
+		tags:   []TrendingTag{},
+	}
+}
+
+func (c *trendingCache) get() ([]TrendingTag, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.tags, c.updatedAt
+}
+
+func (c *trendingCache) set(tags []TrendingTag) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tags = tags
+	c.updatedAt = time.Now()
+}
+
+// refreshTrending recomputes the trending tags every interval. It runs for
+// the lifetime of the server. On error the previous list is kept.
+func (cfg *apiConfig) refreshTrending(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		sendData := database.GetTrendingHashtagsParams{
+			WindowSeconds: int64(cfg.trending.window.Seconds()),
+			PageLimit:     maxTrendingTags,
+		}
+
+		rows, err := cfg.dbQueries.GetTrendingHashtags(context.Background(), sendData)
+		if err != nil {
+			log.Printf("Error refreshing trending hashtags: %s", err)
+		} else {
+			tags := make([]TrendingTag, 0, len(rows))
+			for _, row := range rows {
+				tags = append(tags, TrendingTag{Tag: row.Tag, Count: row.ChirpCount})
+			}
+			cfg.trending.set(tags)
+		}
+
+		<-ticker.C
+	}
+}
+
+func (cfg *apiConfig) handlerGetTrending(w http.ResponseWriter, r *http.Request) {
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid limit", err)
+		return
+	}
+
+	tags, updatedAt := cfg.trending.get()
+	if len(tags) > limit {
+		tags = tags[:limit]
+	}
+
+	sendBack := struct {
+		Window    string        `json:"window"`
+		UpdatedAt time.Time     `json:"updated_at"`
+		Tags      []TrendingTag `json:"tags"`
+	}{
+		cfg.trending.window.String(),
+		updatedAt,
+		tags,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// fakeTagDB keeps chirp_hashtags in memory. It only understands the
+// statements saveHashtags and replaceHashtags run.
+type fakeTagDB struct {
+	tags map[uuid.UUID]map[string]bool
+}
+
+func (db *fakeTagDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	switch {
+	case strings.HasPrefix(query, "-- name: CreateHashtags "):
+		return driver.RowsAffected(0), nil
+
+	case strings.HasPrefix(query, "-- name: CreateChirpHashtags "):
+		chirpID := args[0].(uuid.UUID)
+		if db.tags[chirpID] == nil {
+			db.tags[chirpID] = map[string]bool{}
+		}
+		for _, tag := range *args[1].(*pq.StringArray) {
+			db.tags[chirpID][tag] = true
+		}
+		return driver.RowsAffected(0), nil
+
+	case strings.HasPrefix(query, "-- name: DeleteChirpHashtags "):
+		keep := map[string]bool{}
+		for _, tag := range *args[1].(*pq.StringArray) {
+			keep[tag] = true
+		}
+		for tag := range db.tags[args[0].(uuid.UUID)] {
+			if !keep[tag] {
+				delete(db.tags[args[0].(uuid.UUID)], tag)
+			}
+		}
+		return driver.RowsAffected(0), nil
+	}
+
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+func (db *fakeTagDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, fmt.Errorf("unexpected prepare: %s", query)
+}
+
+func (db *fakeTagDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+func (db *fakeTagDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (db *fakeTagDB) chirpTags(chirpID uuid.UUID) []string {
+	tags := []string{}
+	for tag := range db.tags[chirpID] {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func TestReplaceHashtags(t *testing.T) {
+	db := &fakeTagDB{tags: map[uuid.UUID]map[string]bool{}}
+	q := database.New(db)
+	ctx := context.Background()
+
+	chirp := database.Chirp{ID: uuid.New(), Body: "learning #golang with #chirpy"}
+	if err := saveHashtags(ctx, q, chirp); err != nil {
+		t.Fatalf("saveHashtags: %s", err)
+	}
+
+	if got, want := db.chirpTags(chirp.ID), []string{"chirpy", "golang"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("tags after create = %v, want %v", got, want)
+	}
+
+	chirp.Body = "learning #rust with #chirpy"
+	if err := replaceHashtags(ctx, q, chirp); err != nil {
+		t.Fatalf("replaceHashtags: %s", err)
+	}
+
+	if got, want := db.chirpTags(chirp.ID), []string{"chirpy", "rust"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags after edit = %v, want %v", got, want)
+	}
+
+	chirp.Body = "no tags any more"
+	if err := replaceHashtags(ctx, q, chirp); err != nil {
+		t.Fatalf("replaceHashtags: %s", err)
+	}
+
+	if got := db.chirpTags(chirp.ID); len(got) != 0 {
+		t.Errorf("tags after removing them all = %v, want none", got)
+	}
+}
@@ -1,11 +1,21 @@
 package auth
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 )
@@ -14,13 +24,16 @@ func TestMakeJWT(t *testing.T) {
 
 	godotenv.Load()
 	tokenSecret := os.Getenv("TOKEN_STRING")
+	if tokenSecret == "" {
+		tokenSecret = "secret"
+	}
 
 	id, err := uuid.Parse("bbbff1ab-2214-4f9a-a0a6-1789526c61ad")
 	if err != nil {
 		t.Fatalf("error parsing id: %s", err)
 	}
 
-	token, err := MakeJWT(id, tokenSecret, time.Duration(10))
+	token, err := MakeJWT(id, RoleUser, NewKeySet(tokenSecret), time.Duration(10))
 	if err != nil {
 		t.Fatalf("error making jwt: %s", err)
 	}
@@ -32,6 +45,10 @@ func TestValidateJWT(t *testing.T) {
 
 	godotenv.Load()
 	tokenSecret := os.Getenv("TOKEN_STRING")
+	if tokenSecret == "" {
+		tokenSecret = "secret"
+	}
+	keys := NewKeySet(tokenSecret)
 
 	id, err := uuid.Parse("bbbff1ab-2214-4f9a-a0a6-1789526c61ad")
 	if err != nil {
@@ -40,12 +57,12 @@ func TestValidateJWT(t *testing.T) {
 
 	duration, _ := time.ParseDuration("15s")
 
-	token, err := MakeJWT(id, tokenSecret, duration)
+	token, err := MakeJWT(id, RoleUser, keys, duration)
 	if err != nil {
 		t.Fatalf("error making jwt: %s", err)
 	}
 
-	returnedID, err := ValidateJWT(token, tokenSecret)
+	returnedID, err := ValidateJWT(token, keys)
 	if err != nil {
 		t.Fatalf("error validating jwt: %s", err)
 	}
@@ -53,6 +70,422 @@ func TestValidateJWT(t *testing.T) {
 	t.Log(returnedID)
 }
 
+func TestValidateJWTClaims(t *testing.T) {
+
+	id, err := uuid.Parse("bbbff1ab-2214-4f9a-a0a6-1789526c61ad")
+	if err != nil {
+		t.Fatalf("error parsing id: %s", err)
+	}
+
+	token, err := MakeJWT(id, RoleModerator, NewKeySet("secret"), time.Minute)
+	if err != nil {
+		t.Fatalf("error making jwt: %s", err)
+	}
+
+	claims, err := ValidateJWTClaims(token, NewKeySet("secret"))
+	if err != nil {
+		t.Fatalf("error validating jwt: %s", err)
+	}
+
+	if claims.Subject != id.String() {
+		t.Errorf("ValidateJWTClaims() subject = %v, want %v", claims.Subject, id)
+	}
+
+	if claims.Role != RoleModerator {
+		t.Errorf("ValidateJWTClaims() role = %v, want %v", claims.Role, RoleModerator)
+	}
+
+	if _, err := ValidateJWTClaims(token, NewKeySet("wrong-secret")); err == nil {
+		t.Errorf("ValidateJWTClaims() accepted a token signed with another secret")
+	}
+}
+
+func TestKeySetSigning(t *testing.T) {
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating RSA key: %s", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating Ed25519 key: %s", err)
+	}
+
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		key     crypto.Signer
+		wantAlg string
+	}{
+		{name: "RS256", key: rsaKey, wantAlg: "RS256"},
+		{name: "EdDSA", key: edKey, wantAlg: "EdDSA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			keys := NewKeySet("secret")
+			kid, err := keys.AddSigningKey(tt.key)
+			if err != nil {
+				t.Fatalf("error adding key: %s", err)
+			}
+
+			token, err := MakeJWT(id, RoleUser, keys, time.Minute)
+			if err != nil {
+				t.Fatalf("error making jwt: %s", err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			if err != nil {
+				t.Fatalf("error parsing jwt: %s", err)
+			}
+
+			if parsed.Method.Alg() != tt.wantAlg || parsed.Header["kid"] != kid {
+				t.Errorf("header = %v, want alg %v and kid %v", parsed.Header, tt.wantAlg, kid)
+			}
+
+			if got, err := ValidateJWT(token, keys); err != nil || got != id {
+				t.Errorf("ValidateJWT() = %v, %v, want %v", got, err, id)
+			}
+
+			// Other services only get the public key
+			verifier := NewKeySet("")
+			if _, err := verifier.AddVerificationKey(tt.key.Public()); err != nil {
+				t.Fatalf("error adding verification key: %s", err)
+			}
+
+			if _, err := ValidateJWT(token, verifier); err != nil {
+				t.Errorf("ValidateJWT() with public key failed: %s", err)
+			}
+
+			if _, err := ValidateJWT(token, NewKeySet("secret")); err == nil {
+				t.Errorf("ValidateJWT() accepted a token with an unknown kid")
+			}
+		})
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+
+	_, oldKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, newKey, _ := ed25519.GenerateKey(rand.Reader)
+	id := uuid.New()
+
+	keys := NewKeySet("secret")
+	if _, err := keys.AddSigningKey(oldKey); err != nil {
+		t.Fatalf("error adding key: %s", err)
+	}
+
+	oldToken, err := MakeJWT(id, RoleUser, keys, time.Minute)
+	if err != nil {
+		t.Fatalf("error making jwt: %s", err)
+	}
+
+	newKid, err := keys.AddSigningKey(newKey)
+	if err != nil {
+		t.Fatalf("error adding key: %s", err)
+	}
+
+	newToken, err := MakeJWT(id, RoleUser, keys, time.Minute)
+	if err != nil {
+		t.Fatalf("error making jwt: %s", err)
+	}
+
+	parsed, _, _ := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if parsed.Header["kid"] != newKid {
+		t.Errorf("new token kid = %v, want %v", parsed.Header["kid"], newKid)
+	}
+
+	for name, token := range map[string]string{"old key": oldToken, "new key": newToken} {
+		if _, err := ValidateJWT(token, keys); err != nil {
+			t.Errorf("ValidateJWT() rejected %s token: %s", name, err)
+		}
+	}
+
+	if got := len(keys.JWKS().Keys); got != 2 {
+		t.Errorf("JWKS() has %d keys, want 2", got)
+	}
+}
+
+func TestKeySetHS256Transition(t *testing.T) {
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	legacyToken, err := MakeJWT(uuid.New(), RoleUser, NewKeySet("secret"), time.Minute)
+	if err != nil {
+		t.Fatalf("error making jwt: %s", err)
+	}
+
+	keys := NewKeySet("secret")
+	if _, err := keys.AddSigningKey(edKey); err != nil {
+		t.Fatalf("error adding key: %s", err)
+	}
+
+	// Holding the secret must not be enough to mint tokens once a signing
+	// key is configured
+	if _, err := ValidateJWT(legacyToken, keys); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("ValidateJWT() of HS256 token = %v, want %v", err, ErrTokenSignature)
+	}
+
+	keys.AcceptHS256Until(time.Now().Add(time.Hour))
+	if _, err := ValidateJWT(legacyToken, keys); err != nil {
+		t.Errorf("ValidateJWT() rejected HS256 token during the transition: %s", err)
+	}
+
+	keys.AcceptHS256Until(time.Now().Add(-time.Second))
+	if _, err := ValidateJWT(legacyToken, keys); err == nil {
+		t.Errorf("ValidateJWT() accepted HS256 token after the transition")
+	}
+}
+
+func TestKeySetAlgorithmConfusion(t *testing.T) {
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	keys := NewKeySet("")
+	kid, err := keys.AddSigningKey(edKey)
+	if err != nil {
+		t.Fatalf("error adding key: %s", err)
+	}
+
+	// An HS256 token using the public key as its secret must not verify
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims(time.Minute))
+	forged.Header["kid"] = kid
+	forgedString, err := forged.SignedString([]byte(edKey.Public().(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatalf("error signing forged token: %s", err)
+	}
+
+	if _, err := ValidateJWT(forgedString, keys); err == nil {
+		t.Errorf("ValidateJWT() accepted an HS256 token for an EdDSA key")
+	}
+
+	// Without an HS256 secret, tokens without a kid are refused
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims(time.Minute))
+	unsignedString, _ := unsigned.SignedString([]byte("secret"))
+	if _, err := ValidateJWT(unsignedString, keys); err == nil {
+		t.Errorf("ValidateJWT() accepted a token without a kid")
+	}
+}
+
+// validClaims returns claims that pass every check, for tests to break one
+// at a time.
+func validClaims(expiresIn time.Duration) *Claims {
+	return &Claims{
+		Role: RoleUser,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Audience:  jwt.ClaimStrings{Audience},
+			Subject:   uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+	}
+}
+
+func TestValidateJWTErrors(t *testing.T) {
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	keys := NewKeySet("secret")
+	kid, err := keys.AddSigningKey(edKey)
+	if err != nil {
+		t.Fatalf("error adding key: %s", err)
+	}
+	keys.SetLeeway(30 * time.Second)
+
+	sign := func(claims *Claims, key ed25519.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+		token.Header["kid"] = kid
+		ss, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("error signing token: %s", err)
+		}
+		return ss
+	}
+
+	wrongIssuer := validClaims(time.Minute)
+	wrongIssuer.Issuer = "someone-else"
+
+	wrongAudience := validClaims(time.Minute)
+	wrongAudience.Audience = jwt.ClaimStrings{"another-api"}
+
+	noExpiry := validClaims(time.Minute)
+	noExpiry.ExpiresAt = nil
+
+	noneToken, _ := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims(time.Minute)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "Valid", token: sign(validClaims(time.Minute), edKey), wantErr: nil},
+		{name: "Expired within leeway", token: sign(validClaims(-10*time.Second), edKey), wantErr: nil},
+		{name: "Expired", token: sign(validClaims(-time.Minute), edKey), wantErr: ErrTokenExpired},
+		{name: "Wrong issuer", token: sign(wrongIssuer, edKey), wantErr: ErrTokenInvalidClaims},
+		{name: "Wrong audience", token: sign(wrongAudience, edKey), wantErr: ErrTokenInvalidClaims},
+		{name: "No expiry", token: sign(noExpiry, edKey), wantErr: ErrTokenInvalidClaims},
+		{name: "Bad signature", token: sign(validClaims(time.Minute), otherKey), wantErr: ErrTokenSignature},
+		{name: "Algorithm none", token: noneToken, wantErr: ErrTokenSignature},
+		{name: "Malformed", token: "not.a.jwt", wantErr: ErrTokenMalformed},
+		{name: "Empty", token: "", wantErr: ErrTokenMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateJWT(tt.token, keys)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ValidateJWT() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateJWT() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWWWAuthenticate(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "No token",
+			err:  fmt.Errorf("%w: no authorization", ErrNoBearerToken),
+			want: `Bearer realm="chirpy"`,
+		},
+		{
+			name: "Expired",
+			err:  fmt.Errorf("%w: details", ErrTokenExpired),
+			want: `Bearer realm="chirpy", error="invalid_token", error_description="The access token expired"`,
+		},
+		{
+			name: "Bad signature",
+			err:  fmt.Errorf("%w: details", ErrTokenSignature),
+			want: `Bearer realm="chirpy", error="invalid_token", error_description="The access token signature is invalid"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WWWAuthenticate("chirpy", tt.err); got != tt.want {
+				t.Errorf("WWWAuthenticate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+
+	// Example from RFC 7638 section 3.1
+	jwk := JWK{
+		Kty: "RSA",
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:   "AQAB",
+		Alg: "RS256",
+		Kid: "2011-04-29",
+	}
+
+	want := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	if got := jwk.thumbprint(); got != want {
+		t.Errorf("thumbprint() = %v, want %v", got, want)
+	}
+}
+
+func TestParseKeyPEM(t *testing.T) {
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatalf("error marshalling key: %s", err)
+	}
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+
+	pubDER, err := x509.MarshalPKIXPublicKey(edKey.Public())
+	if err != nil {
+		t.Fatalf("error marshalling key: %s", err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	signer, err := ParsePrivateKeyPEM(privPEM)
+	if err != nil {
+		t.Fatalf("ParsePrivateKeyPEM() error: %s", err)
+	}
+	if !edKey.Equal(signer) {
+		t.Errorf("ParsePrivateKeyPEM() returned a different key")
+	}
+
+	for name, data := range map[string][]byte{"public": pubPEM, "private": privPEM} {
+		pub, err := ParsePublicKeyPEM(data)
+		if err != nil {
+			t.Fatalf("ParsePublicKeyPEM(%s) error: %s", name, err)
+		}
+		if !edKey.Public().(ed25519.PublicKey).Equal(pub) {
+			t.Errorf("ParsePublicKeyPEM(%s) returned a different key", name)
+		}
+	}
+
+	if _, err := ParsePrivateKeyPEM([]byte("not a key")); err == nil {
+		t.Errorf("ParsePrivateKeyPEM() accepted garbage")
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		required string
+		want     bool
+	}{
+		{name: "Admin can moderate", role: RoleAdmin, required: RoleModerator, want: true},
+		{name: "Moderator can moderate", role: RoleModerator, required: RoleModerator, want: true},
+		{name: "User cannot moderate", role: RoleUser, required: RoleModerator, want: false},
+		{name: "Moderator cannot administer", role: RoleModerator, required: RoleAdmin, want: false},
+		{name: "Unknown role", role: "superuser", required: RoleUser, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasRole(tt.role, tt.required); got != tt.want {
+				t.Errorf("HasRole(%v, %v) = %v, want %v", tt.role, tt.required, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutranks(t *testing.T) {
+	tests := []struct {
+		name  string
+		role  string
+		other string
+		want  bool
+	}{
+		{name: "Admin outranks moderator", role: RoleAdmin, other: RoleModerator, want: true},
+		{name: "Moderator outranks user", role: RoleModerator, other: RoleUser, want: true},
+		{name: "Moderator does not outrank moderator", role: RoleModerator, other: RoleModerator, want: false},
+		{name: "Moderator does not outrank admin", role: RoleModerator, other: RoleAdmin, want: false},
+		{name: "Admin does not outrank admin", role: RoleAdmin, other: RoleAdmin, want: false},
+		{name: "Unknown role", role: "superuser", other: RoleUser, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Outranks(tt.role, tt.other); got != tt.want {
+				t.Errorf("Outranks(%v, %v) = %v, want %v", tt.role, tt.other, got, tt.want)
+			}
+		})
+	}
+}
+
 // Unit test for GetBearerToken function
 func TestGetBearerToken(t *testing.T) {
 	tests := []struct {
@@ -91,6 +524,22 @@ func TestGetBearerToken(t *testing.T) {
 			wantToken: "",
 			expectErr: true,
 		},
+		{
+			name: "Bearer without a token",
+			headers: http.Header{
+				"Authorization": {"Bearer"},
+			},
+			wantToken: "",
+			expectErr: true,
+		},
+		{
+			name: "Wrong scheme",
+			headers: http.Header{
+				"Authorization": {"Basic dXNlcjpwYXNz"},
+			},
+			wantToken: "",
+			expectErr: true,
+		},
 		{
 			name: "Empty token after Bearer",
 			headers: http.Header{
@@ -179,3 +628,50 @@ func TestGetAPIKey(t *testing.T) {
 		})
 	}
 }
+
+func TestSignedToken(t *testing.T) {
+
+	token, err := MakeSignedToken("secret")
+	if err != nil {
+		t.Fatalf("error making signed token: %s", err)
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		secret    string
+		expectErr bool
+	}{
+		{name: "Valid token", token: token, secret: "secret", expectErr: false},
+		{name: "Wrong secret", token: token, secret: "other", expectErr: true},
+		{name: "Tampered value", token: "00" + token[2:], secret: "secret", expectErr: true},
+		{name: "Missing signature", token: strings.Split(token, ".")[0], secret: "secret", expectErr: true},
+		{name: "Empty token", token: "", secret: "secret", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignedToken(tt.token, tt.secret)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("VerifySignedToken() error = %v, wantErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestHashToken(t *testing.T) {
+
+	if HashToken("abc") != HashToken("abc") {
+		t.Errorf("HashToken() is not deterministic")
+	}
+
+	if HashToken("abc") == HashToken("abd") {
+		t.Errorf("HashToken() returned the same hash for different tokens")
+	}
+
+	// sha256("abc")
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := HashToken("abc"); got != want {
+		t.Errorf("HashToken() = %v, want %v", got, want)
+	}
+}
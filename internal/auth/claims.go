@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// Issuer and Audience are set on every access token and required when
+	// validating one, so tokens minted for another service are refused
+	Issuer   = "chirpy"
+	Audience = "chirpy-api"
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// roleRank orders roles so a higher role can do everything a lower one can
+var roleRank = map[string]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
+}
+
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// ValidRole reports whether role is one of the known roles.
+func ValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// HasRole reports whether role grants at least the access of required.
+func HasRole(role, required string) bool {
+	return ValidRole(role) && roleRank[role] >= roleRank[required]
+}
+
+// Outranks reports whether role is strictly higher than other, which is what
+// it takes to act on another user's account.
+func Outranks(role, other string) bool {
+	return HasRole(role, other) && roleRank[role] > roleRank[other]
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by GetBearerToken and ValidateJWT. Check them with
+// errors.Is; the returned error carries the details.
+var (
+	ErrNoBearerToken      = errors.New("no bearer token")
+	ErrTokenMalformed     = errors.New("token is malformed")
+	ErrTokenSignature     = errors.New("token signature is invalid")
+	ErrTokenExpired       = errors.New("token is expired")
+	ErrTokenInvalidClaims = errors.New("token claims are invalid")
+)
+
+// WWWAuthenticate builds the RFC 6750 WWW-Authenticate header for a request
+// rejected with err. A request without a token only gets the challenge, so
+// clients can tell "log in" apart from "this token is no good".
+func WWWAuthenticate(realm string, err error) string {
+
+	challenge := fmt.Sprintf("Bearer realm=%q", realm)
+	if errors.Is(err, ErrNoBearerToken) {
+		return challenge
+	}
+
+	description := "The access token is invalid"
+	switch {
+	case errors.Is(err, ErrTokenExpired):
+		description = "The access token expired"
+	case errors.Is(err, ErrTokenMalformed):
+		description = "The access token is malformed"
+	case errors.Is(err, ErrTokenSignature):
+		description = "The access token signature is invalid"
+	case errors.Is(err, ErrTokenInvalidClaims):
+		description = "The access token claims are invalid"
+	}
+
+	return fmt.Sprintf("%s, error=\"invalid_token\", error_description=%q", challenge, description)
+}
@@ -9,15 +9,13 @@ import (
 func GetBearerToken(headers http.Header) (string, error) {
 	authHeader := headers.Get("Authorization")
 	if authHeader == "" {
-		return "", fmt.Errorf("no authorization")
+		return "", fmt.Errorf("%w: no authorization", ErrNoBearerToken)
 	}
 
-	tokenBearer := strings.Split(authHeader, " ")[0]
-	if tokenBearer != "Bearer" {
-		return "", fmt.Errorf("invalid format")
+	tokenBearer, tokenString, ok := strings.Cut(authHeader, " ")
+	if !ok || tokenBearer != "Bearer" {
+		return "", fmt.Errorf("%w: invalid format", ErrNoBearerToken)
 	}
 
-	tokenString := strings.Split(authHeader, " ")[1]
-
 	return tokenString, nil
 }
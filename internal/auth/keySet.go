@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtKey is one key in a KeySet. signKey is nil for keys that are only kept
+// to verify tokens issued before a rotation.
+type jwtKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   crypto.Signer
+	verifyKey crypto.PublicKey
+}
+
+// KeySet holds the keys access tokens are signed and verified with. One key
+// signs new tokens and every key in the set verifies them, so a new key can
+// be rolled out while tokens signed by the old one are still live. Keys are
+// identified by the kid header, which is their RFC 7638 thumbprint.
+//
+// The HS256 secret is a fallback for when no asymmetric key is configured: it
+// signs tokens and verifies tokens without a kid, which is how tokens were
+// issued before key sets existed. Once a signing key is added, anyone holding
+// the secret could still mint tokens, so HS256 is refused unless
+// AcceptHS256Until allows it for a transition period. HS256 keys are never
+// published.
+//
+// Keys are added at startup; a KeySet must not be changed once it is in use.
+type KeySet struct {
+	hmacSecret []byte
+	hmacUntil  time.Time
+	leeway     time.Duration
+	signing    *jwtKey
+	keys       map[string]*jwtKey
+	order      []string
+}
+
+func NewKeySet(hmacSecret string) *KeySet {
+	ks := &KeySet{keys: make(map[string]*jwtKey)}
+	if hmacSecret != "" {
+		ks.hmacSecret = []byte(hmacSecret)
+	}
+	return ks
+}
+
+// SetLeeway allows for clock skew between us and other services when
+// checking the exp, nbf and iat claims.
+func (ks *KeySet) SetLeeway(leeway time.Duration) {
+	ks.leeway = leeway
+}
+
+// AcceptHS256Until keeps verifying HS256 tokens after a signing key has been
+// added, until the given time, so tokens issued before the switch stay valid
+// while they expire.
+func (ks *KeySet) AcceptHS256Until(until time.Time) {
+	ks.hmacUntil = until
+}
+
+// acceptsHS256 reports whether tokens signed with the fallback secret are
+// still valid at now.
+func (ks *KeySet) acceptsHS256(now time.Time) bool {
+	if ks.hmacSecret == nil {
+		return false
+	}
+	return ks.signing == nil || now.Before(ks.hmacUntil)
+}
+
+// validMethods lists the algorithms a token may use. HS256 is only allowed
+// while the fallback secret is in use.
+func (ks *KeySet) validMethods() []string {
+
+	methods := []string{}
+	seen := make(map[string]bool)
+
+	for _, kid := range ks.order {
+		alg := ks.keys[kid].method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+
+	if ks.acceptsHS256(time.Now()) {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	return methods
+}
+
+// AddSigningKey makes key the one new tokens are signed with. It also keeps
+// verifying tokens, as does the key it replaces. RSA and Ed25519 keys are
+// supported.
+func (ks *KeySet) AddSigningKey(key crypto.Signer) (string, error) {
+
+	k, err := newJWTKey(key.Public())
+	if err != nil {
+		return "", err
+	}
+	k.signKey = key
+
+	ks.add(k)
+	ks.signing = k
+
+	return k.id, nil
+}
+
+// AddVerificationKey accepts tokens signed by the private half of key
+// without using it to sign new ones.
+func (ks *KeySet) AddVerificationKey(key crypto.PublicKey) (string, error) {
+
+	k, err := newJWTKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	ks.add(k)
+
+	return k.id, nil
+}
+
+// add keeps the private half of a key that is added twice, once for signing
+// and once for verification.
+func (ks *KeySet) add(k *jwtKey) {
+	if existing, ok := ks.keys[k.id]; ok {
+		if k.signKey == nil {
+			k.signKey = existing.signKey
+		}
+	} else {
+		ks.order = append(ks.order, k.id)
+	}
+	ks.keys[k.id] = k
+}
+
+func newJWTKey(pub crypto.PublicKey) (*jwtKey, error) {
+
+	var method jwt.SigningMethod
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		// Anything shorter can be factored or is refused by other verifiers
+		if key.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA key must be at least 2048 bits")
+		}
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", pub)
+	}
+
+	jwk, err := publicJWK(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jwtKey{
+		id:        jwk.thumbprint(),
+		method:    method,
+		verifyKey: pub,
+	}, nil
+}
+
+// signer returns the method, kid and key new tokens are signed with. The kid
+// is empty for the HS256 fallback.
+func (ks *KeySet) signer() (jwt.SigningMethod, string, interface{}, error) {
+
+	if ks.signing != nil {
+		return ks.signing.method, ks.signing.id, ks.signing.signKey, nil
+	}
+
+	if ks.hmacSecret != nil {
+		return jwt.SigningMethodHS256, "", ks.hmacSecret, nil
+	}
+
+	return nil, "", nil, fmt.Errorf("no signing key configured")
+}
+
+// keyFunc picks the verification key for a token. The algorithm has to be
+// the one the key is meant for, so a public key can never be used as an
+// HMAC secret.
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if !ks.acceptsHS256(time.Now()) || token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("token has no kid")
+		}
+		return ks.hmacSecret, nil
+	}
+
+	k, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+
+	if token.Method != k.method {
+		return nil, fmt.Errorf("unexpected signing method %s for kid %q", token.Method.Alg(), kid)
+	}
+
+	return k.verifyKey, nil
+}
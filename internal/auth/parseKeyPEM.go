@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// ParsePrivateKeyPEM reads an RSA or Ed25519 private key in PKCS #8 or,
+// for RSA, PKCS #1 form, as written by openssl genpkey or genrsa.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("error decoding key: no PEM block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("error decoding key: unexpected PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key: %s", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("error parsing private key: unsupported key type %T", key)
+	}
+
+	return signer, nil
+}
+
+// ParsePublicKeyPEM reads a public key, or the public half of a private
+// key, so retired keys can be kept for verification without their secret.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("error decoding key: no PEM block found")
+	}
+
+	if block.Type == "PUBLIC KEY" {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing public key: %s", err)
+		}
+		return key, nil
+	}
+
+	signer, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.Public(), nil
+}
@@ -1,31 +1,74 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
-func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
-
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(tokenSecret), nil
-	})
+func ValidateJWT(tokenString string, keys *KeySet) (uuid.UUID, error) {
 
+	claims, err := ValidateJWTClaims(tokenString, keys)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("error parsing claims: %s", err)
+		return uuid.Nil, err
 	}
 
-	idString, err := token.Claims.GetSubject()
+	id, err := uuid.Parse(claims.Subject)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("error obtaining id from claims: %s", err)
+		return uuid.Nil, fmt.Errorf("%w: error parsing id: %s", ErrTokenInvalidClaims, err)
 	}
 
-	id, err := uuid.Parse(idString)
+	return id, nil
+}
+
+// ValidateJWTClaims checks the token and returns all of its claims, for
+// callers that need more than the user id. Besides the signature, the token
+// must use an algorithm one of our keys uses, come from our issuer for our
+// audience and carry an expiry. Errors wrap one of the ErrToken errors.
+func ValidateJWTClaims(tokenString string, keys *KeySet) (*Claims, error) {
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(keys.validMethods()),
+		jwt.WithIssuer(Issuer),
+		jwt.WithAudience(Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(keys.leeway),
+	)
+
+	claims := &Claims{}
+	_, err := parser.ParseWithClaims(tokenString, claims, keys.keyFunc)
+
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("error parsing id: %s", err)
+		return nil, fmt.Errorf("%w: %s", classifyJWTError(err), err)
 	}
 
-	return id, nil
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrTokenInvalidClaims)
+	}
+
+	// Tokens issued before roles existed carry no role claim
+	if claims.Role == "" {
+		claims.Role = RoleUser
+	}
+
+	return claims, nil
+}
+
+// classifyJWTError maps the jwt library's errors onto ours. Expiry is
+// checked first because an expired token is also reported as having
+// invalid claims.
+func classifyJWTError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return ErrTokenMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return ErrTokenSignature
+	}
+
+	return ErrTokenInvalidClaims
 }
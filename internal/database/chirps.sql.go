@@ -12,24 +12,28 @@ import (
 )
 
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_id, quoted_chirp_id)
 VALUES (
     gen_random_uuid(),  -- Generates a new UUID
     NOW(),              -- Sets created_at to the current timestamp
     NOW(),              -- Sets updated_at to the current timestamp
     $1,                 -- The body, passed in by the application
-    $2                  -- The user_id, passed in by the application
+    $2,                 -- The user_id, passed in by the application
+    $3,                 -- The chirp being replied to, if any
+    $4                  -- The chirp being quoted, if any
 )
-RETURNING id, created_at, updated_at, body, user_id
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_id, quoted_chirp_id, deleted_at, hidden_at
 `
 
 type CreateChirpParams struct {
-	Body   string
-	UserID uuid.UUID
+	Body          string
+	UserID        uuid.UUID
+	ParentID      uuid.NullUUID
+	QuotedChirpID uuid.NullUUID
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID, arg.ParentID, arg.QuotedChirpID)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -37,6 +41,11 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.QuotedChirpID,
+		&i.DeletedAt,
+		&i.HiddenAt,
 	)
 	return i, err
 }
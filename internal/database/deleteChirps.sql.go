@@ -11,12 +11,51 @@ import (
 	"github.com/google/uuid"
 )
 
-const deleteChirp = `-- name: DeleteChirp :exec
+const purgeDeletedChirps = `-- name: PurgeDeletedChirps :execrows
 DELETE FROM chirps
+WHERE deleted_at < NOW() - ($1::bigint * INTERVAL '1 second')
+`
+
+func (q *Queries) PurgeDeletedChirps(ctx context.Context, retentionSeconds int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedChirps, retentionSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreChirp = `-- name: RestoreChirp :one
+UPDATE chirps
+SET deleted_at = NULL
+WHERE id = $1
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_id, quoted_chirp_id, deleted_at, hidden_at
+`
+
+func (q *Queries) RestoreChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, restoreChirp, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.QuotedChirpID,
+		&i.DeletedAt,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
+const softDeleteChirp = `-- name: SoftDeleteChirp :exec
+UPDATE chirps
+SET deleted_at = NOW()
 WHERE id = $1
 `
 
-func (q *Queries) DeleteChirp(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteChirp, id)
+func (q *Queries) SoftDeleteChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, softDeleteChirp, id)
 	return err
 }
@@ -1,47 +0,0 @@
-// Code generated by sqlc. DO NOT EDIT.
-// versions:
-//   sqlc v1.27.0
-// source: getChirps.sql
-
-package database
-
-import (
-	"context"
-)
-
-const getAllChirps = `-- name: GetAllChirps :many
-SELECT id, created_at, updated_at, body, user_id
-FROM chirps
-ORDER BY
-  CASE WHEN $1::text = 'asc' THEN created_at END ASC,
-  CASE WHEN $1::text  = 'desc' THEN created_at END DESC
-`
-
-func (q *Queries) GetAllChirps(ctx context.Context, dollar_1 string) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getAllChirps, dollar_1)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Chirp
-	for rows.Next() {
-		var i Chirp
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Body,
-			&i.UserID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
@@ -10,7 +10,7 @@ import (
 )
 
 const getHashPassByEmail = `-- name: GetHashPassByEmail :one
-SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, suspended_at, role, email_verified_at, display_name, bio, avatar_url, handle
 FROM users
 WHERE email=$1
 `
@@ -25,6 +25,13 @@ func (q *Queries) GetHashPassByEmail(ctx context.Context, email string) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.SuspendedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.AvatarUrl,
+		&i.Handle,
 	)
 	return i, err
 }
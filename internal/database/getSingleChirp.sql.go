@@ -12,9 +12,9 @@ import (
 )
 
 const getChirpByID = `-- name: GetChirpByID :one
-SELECT id, created_at, updated_at, body, user_id
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_id, quoted_chirp_id, deleted_at, hidden_at
 FROM chirps
-WHERE id=$1
+WHERE id=$1 AND deleted_at IS NULL AND hidden_at IS NULL
 `
 
 func (q *Queries) GetChirpByID(ctx context.Context, id uuid.UUID) (Chirp, error) {
@@ -26,6 +26,66 @@ func (q *Queries) GetChirpByID(ctx context.Context, id uuid.UUID) (Chirp, error)
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.QuotedChirpID,
+		&i.DeletedAt,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
+const getChirpByIDForUser = `-- name: GetChirpByIDForUser :one
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_id, quoted_chirp_id, deleted_at, hidden_at
+FROM chirps
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND (hidden_at IS NULL OR user_id = $2)
+`
+
+type GetChirpByIDForUserParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetChirpByIDForUser(ctx context.Context, arg GetChirpByIDForUserParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpByIDForUser, arg.ID, arg.UserID)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.QuotedChirpID,
+		&i.DeletedAt,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
+const getDeletedChirpByID = `-- name: GetDeletedChirpByID :one
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_id, quoted_chirp_id, deleted_at, hidden_at
+FROM chirps
+WHERE id=$1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) GetDeletedChirpByID(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedChirpByID, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.QuotedChirpID,
+		&i.DeletedAt,
+		&i.HiddenAt,
 	)
 	return i, err
 }
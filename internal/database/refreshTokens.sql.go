@@ -7,30 +7,39 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 const createRefeshToken = `-- name: CreateRefeshToken :exec
-INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at, revoked_at)
+INSERT INTO refresh_tokens (token_hash, created_at, updated_at, user_id, expires_at, revoked_at, family_id, parent_token_hash, user_agent, ip_address)
 VALUES (
     $1,
     NOW(),
     NOW(),
     $2,
     $3,
-    null
+    null,
+    $4,
+    $5,
+    $6,
+    $7
 )
 `
 
 type CreateRefeshTokenParams struct {
-	Token     string
-	UserID    uuid.UUID
-	ExpiresAt time.Time
+	TokenHash       string
+	UserID          uuid.UUID
+	ExpiresAt       time.Time
+	FamilyID        uuid.UUID
+	ParentTokenHash sql.NullString
+	UserAgent       string
+	IpAddress       string
 }
 
 func (q *Queries) CreateRefeshToken(ctx context.Context, arg CreateRefeshTokenParams) error {
-	_, err := q.db.ExecContext(ctx, createRefeshToken, arg.Token, arg.UserID, arg.ExpiresAt)
+	_, err := q.db.ExecContext(ctx, createRefeshToken, arg.TokenHash, arg.UserID, arg.ExpiresAt, arg.FamilyID, arg.ParentTokenHash, arg.UserAgent, arg.IpAddress)
 	return err
 }
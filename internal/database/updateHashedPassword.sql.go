@@ -16,7 +16,7 @@ UPDATE users
 SET updated_at = NOW(),
     hashed_password = $2
 WHERE id = $1
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, suspended_at, role, email_verified_at, display_name, bio, avatar_url, handle
 `
 
 type UpdatePasswordParams struct {
@@ -34,6 +34,13 @@ func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams)
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.SuspendedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.AvatarUrl,
+		&i.Handle,
 	)
 	return i, err
 }
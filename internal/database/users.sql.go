@@ -18,7 +18,7 @@ VALUES (
     $1,                 -- The email, passed in by the application
     $2                  -- The hashedpassword, passed in by the application
 )
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, suspended_at, role, email_verified_at, display_name, bio, avatar_url, handle
 `
 
 type CreateUserParams struct {
@@ -36,6 +36,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.SuspendedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.AvatarUrl,
+		&i.Handle,
 	)
 	return i, err
 }
@@ -0,0 +1,57 @@
+package hashtags
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "No tags", body: "just chirping", want: nil},
+		{name: "Single", body: "learning #golang", want: []string{"golang"}},
+		{name: "Start of body", body: "#golang is fun", want: []string{"golang"}},
+		{name: "Lower cased", body: "#GoLang", want: []string{"golang"}},
+		{name: "Adjacent", body: "#go #sql_tips", want: []string{"go", "sql_tips"}},
+		{name: "Punctuation ends tag", body: "so good #go!", want: []string{"go"}},
+		{name: "Duplicates", body: "#go and #GO", want: []string{"go"}},
+		{name: "Unicode letters", body: "#café", want: []string{"café"}},
+		{name: "Digits only", body: "we're #1", want: nil},
+		{name: "Digits and letters", body: "#web3", want: []string{"web3"}},
+		{name: "URL fragment", body: "see example.com/page#top", want: nil},
+		{name: "Inside word", body: "c#sharp", want: nil},
+		{name: "Double hash", body: "##go", want: nil},
+		{name: "Bare hash", body: "# heading", want: nil},
+		{name: "Too long", body: "#" + strings.Repeat("a", MaxTagLength+1), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "go", want: "go"},
+		{tag: "#Go", want: "go"},
+		{tag: " #SQL ", want: "sql"},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.tag); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package hashtags
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const MaxTagLength = 50
+
+// Parse returns the hashtags in a chirp body, lower cased and without the
+// leading #, without duplicates, in the order they first appear. Like
+// mentions, a # only starts a tag at the beginning of the body or after a
+// character that can't be part of a tag, so URL fragments such as
+// example.com/page#top are not picked up. A tag needs at least one letter,
+// which keeps "#1" from becoming a tag.
+func Parse(body string) []string {
+
+	var tags []string
+	seen := make(map[string]bool)
+
+	for i := 0; i < len(body); {
+		r, size := utf8.DecodeRuneInString(body[i:])
+
+		if r != '#' || !canStartTag(body[:i]) {
+			i += size
+			continue
+		}
+
+		end := i + size
+		hasLetter := false
+		for end < len(body) {
+			next, nextSize := utf8.DecodeRuneInString(body[end:])
+			if !isTagRune(next) {
+				break
+			}
+			hasLetter = hasLetter || unicode.IsLetter(next)
+			end += nextSize
+		}
+
+		tag := strings.ToLower(body[i+size : end])
+		if hasLetter && utf8.RuneCountInString(tag) <= MaxTagLength && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+
+		i = end
+	}
+
+	return tags
+}
+
+// Normalize turns user input such as "#Go" into the stored form "go".
+func Normalize(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// canStartTag reports whether a # following before begins a tag.
+func canStartTag(before string) bool {
+	if before == "" {
+		return true
+	}
+
+	prev, _ := utf8.DecodeLastRuneInString(before)
+	return !isTagRune(prev) && prev != '#' && prev != '/'
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogMailer(t *testing.T) {
+
+	var buf bytes.Buffer
+	m := NewLogMailer(&buf)
+
+	msg := Message{To: "user@example.com", Subject: "Hello", Body: "token: abc"}
+	if err := m.Send(context.Background(), msg); err != nil {
+		t.Fatalf("error sending mail: %s", err)
+	}
+
+	for _, want := range []string{"To: user@example.com", "Subject: Hello", "token: abc"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("LogMailer output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+
+	date := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	msg := Message{
+		To:      "user@example.com\r\nBcc: victim@example.com",
+		Subject: "Verify",
+		Body:    "line one\nline two",
+	}
+
+	got := string(buildMessage("chirpy@example.com", msg, date))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "From header", want: "From: chirpy@example.com\r\n"},
+		{name: "Header injection stripped", want: "To: user@example.comBcc: victim@example.com\r\n"},
+		{name: "Date header", want: "Date: Sat, 17 Oct 2026 12:00:00 +0000\r\n"},
+		{name: "Body uses CRLF", want: "\r\n\r\nline one\r\nline two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("buildMessage() missing %q in:\n%q", tt.want, got)
+			}
+		})
+	}
+}
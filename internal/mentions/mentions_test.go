@@ -0,0 +1,37 @@
+package mentions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "No mentions", body: "just chirping", want: nil},
+		{name: "Single", body: "hi @alice", want: []string{"alice"}},
+		{name: "Start of body", body: "@alice hi", want: []string{"alice"}},
+		{name: "Lower cased", body: "hi @Alice", want: []string{"alice"}},
+		{name: "Adjacent", body: "@alice @bob_2", want: []string{"alice", "bob_2"}},
+		{name: "Punctuation ends handle", body: "thanks @alice!", want: []string{"alice"}},
+		{name: "Duplicates", body: "@alice and @ALICE", want: []string{"alice"}},
+		{name: "Email address", body: "mail me at bob@example.com", want: nil},
+		{name: "Double at", body: "@@alice", want: nil},
+		{name: "Too short", body: "@al", want: nil},
+		{name: "Too long", body: "@abcdefghijklmnop", want: nil},
+		{name: "After emoji", body: "🐦@alice", want: []string{"alice"}},
+		{name: "Bare at", body: "meet @ noon", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
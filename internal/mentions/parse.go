@@ -0,0 +1,53 @@
+package mentions
+
+import (
+	"strings"
+)
+
+const (
+	MinHandleLength = 3
+	MaxHandleLength = 15
+)
+
+// Parse returns the handles mentioned in a chirp body, lower cased and
+// without duplicates, in the order they first appear. An @ only starts a
+// mention at the beginning of the body or after a character that can't be
+// part of a handle, so email addresses are not picked up. Tokens that are
+// too short or too long to be a handle are ignored rather than truncated.
+func Parse(body string) []string {
+
+	var handles []string
+	seen := make(map[string]bool)
+
+	for i := 0; i < len(body); i++ {
+		if body[i] != '@' {
+			continue
+		}
+
+		if i > 0 && (isHandleChar(body[i-1]) || body[i-1] == '@') {
+			continue
+		}
+
+		end := i + 1
+		for end < len(body) && isHandleChar(body[end]) {
+			end++
+		}
+
+		handle := strings.ToLower(body[i+1 : end])
+		if len(handle) >= MinHandleLength && len(handle) <= MaxHandleLength && !seen[handle] {
+			seen[handle] = true
+			handles = append(handles, handle)
+		}
+
+		i = end - 1
+	}
+
+	return handles
+}
+
+func isHandleChar(c byte) bool {
+	return c >= 'a' && c <= 'z' ||
+		c >= 'A' && c <= 'Z' ||
+		c >= '0' && c <= '9' ||
+		c == '_'
+}
@@ -0,0 +1,54 @@
+package moderation
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadFile reads a word list with one word per line, optionally followed by
+// an action. Words without an action are masked. Blank lines and lines
+// starting with # are ignored.
+func LoadFile(path string) ([]Entry, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening word list: %s", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected word and optional action", lineNumber)
+		}
+
+		action := ActionMask
+		if len(fields) == 2 {
+			action, err = ParseAction(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", lineNumber, err)
+			}
+		}
+
+		entries = append(entries, Entry{Word: fields[0], Action: action})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading word list: %s", err)
+	}
+
+	return entries, nil
+}
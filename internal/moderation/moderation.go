@@ -0,0 +1,46 @@
+package moderation
+
+import (
+	"fmt"
+)
+
+// Action is what happens to a chirp when a banned word is found in it.
+type Action string
+
+const (
+	// ActionMask replaces the word with asterisks.
+	ActionMask Action = "mask"
+	// ActionReject refuses the chirp outright.
+	ActionReject Action = "reject"
+	// ActionFlag keeps the chirp as written but queues it for review.
+	ActionFlag Action = "flag"
+)
+
+func ParseAction(s string) (Action, error) {
+	switch Action(s) {
+	case ActionMask, ActionReject, ActionFlag:
+		return Action(s), nil
+	}
+
+	return "", fmt.Errorf("invalid action: %q", s)
+}
+
+// Entry is a single banned word and the action taken when it is found.
+type Entry struct {
+	Word   string
+	Action Action
+}
+
+// Result is the outcome of running text through a Filter.
+type Result struct {
+	// Text is the input with every masked word replaced.
+	Text     string
+	Rejected bool
+	Flagged  bool
+	Matches  []Entry
+}
+
+// Filter decides what to do with user-submitted text.
+type Filter interface {
+	Check(text string) Result
+}
@@ -0,0 +1,180 @@
+package moderation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWordListCheck(t *testing.T) {
+
+	wl, err := NewWordList([]Entry{
+		{Word: "kerfuffle", Action: ActionMask},
+		{Word: "fornax", Action: ActionReject},
+		{Word: "sharbert", Action: ActionFlag},
+		{Word: "café", Action: ActionMask},
+	})
+	if err != nil {
+		t.Fatalf("error creating word list: %s", err)
+	}
+
+	tests := []struct {
+		name         string
+		text         string
+		wantText     string
+		wantRejected bool
+		wantFlagged  bool
+	}{
+		{
+			name:     "Clean text",
+			text:     "I had something interesting for breakfast",
+			wantText: "I had something interesting for breakfast",
+		},
+		{
+			name:     "Masks whole word",
+			text:     "What a kerfuffle this is",
+			wantText: "What a **** this is",
+		},
+		{
+			name:     "Ignores case",
+			text:     "KerFUFFLE!",
+			wantText: "****!",
+		},
+		{
+			name:     "Leaves substrings alone",
+			text:     "kerfuffles and kerfufflement",
+			wantText: "kerfuffles and kerfufflement",
+		},
+		{
+			name:     "Matches unicode words",
+			text:     "CAFÉ, Café or cafés",
+			wantText: "****, **** or cafés",
+		},
+		{
+			name:     "Unicode word boundaries",
+			text:     "«kerfuffle»",
+			wantText: "«****»",
+		},
+		{
+			name:         "Rejects",
+			text:         "the Fornax cluster",
+			wantText:     "the Fornax cluster",
+			wantRejected: true,
+		},
+		{
+			name:        "Flags",
+			text:        "sharbert again",
+			wantText:    "sharbert again",
+			wantFlagged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wl.Check(tt.text)
+
+			if got.Text != tt.wantText {
+				t.Errorf("Check().Text = %q, want %q", got.Text, tt.wantText)
+			}
+
+			if got.Rejected != tt.wantRejected {
+				t.Errorf("Check().Rejected = %v, want %v", got.Rejected, tt.wantRejected)
+			}
+
+			if got.Flagged != tt.wantFlagged {
+				t.Errorf("Check().Flagged = %v, want %v", got.Flagged, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+func TestWordListEdit(t *testing.T) {
+
+	wl, err := NewWordList(nil)
+	if err != nil {
+		t.Fatalf("error creating word list: %s", err)
+	}
+
+	if _, err := wl.Set("Fornax", ActionMask); err != nil {
+		t.Fatalf("error setting word: %s", err)
+	}
+
+	if got := wl.Check("fornax").Text; got != "****" {
+		t.Errorf("Check() after Set = %q, want %q", got, "****")
+	}
+
+	if _, err := wl.Set("fornax", ActionReject); err != nil {
+		t.Fatalf("error updating word: %s", err)
+	}
+
+	if entries := wl.Entries(); len(entries) != 1 || entries[0].Action != ActionReject {
+		t.Errorf("Entries() = %v, want a single rejected word", entries)
+	}
+
+	if entry, ok := wl.Lookup("FORNAX"); !ok || entry.Word != "fornax" {
+		t.Errorf("Lookup() = %v, %v, want the stored lower case word", entry, ok)
+	}
+
+	if !wl.Remove("FORNAX") {
+		t.Errorf("Remove() = false, want true")
+	}
+
+	if wl.Remove("fornax") {
+		t.Errorf("Remove() of missing word = true, want false")
+	}
+
+	if _, ok := wl.Lookup("fornax"); ok {
+		t.Errorf("Lookup() after Remove = true, want false")
+	}
+
+	if entry, err := NewEntry("  BadWord ", ActionFlag); err != nil || entry.Word != "badword" {
+		t.Errorf("NewEntry() = %v, %v, want %q", entry, err, "badword")
+	}
+
+	if _, err := wl.Set("two words", ActionMask); err == nil {
+		t.Errorf("Set() with a space expected error")
+	}
+
+	if _, err := wl.Set("word", Action("delete")); err == nil {
+		t.Errorf("Set() with unknown action expected error")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "words.txt")
+	content := "# banned words\nkerfuffle\n\nfornax reject\nsharbert flag\n"
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("error writing word list: %s", err)
+	}
+
+	entries, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("error loading word list: %s", err)
+	}
+
+	want := []Entry{
+		{Word: "kerfuffle", Action: ActionMask},
+		{Word: "fornax", Action: ActionReject},
+		{Word: "sharbert", Action: ActionFlag},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("LoadFile() = %v, want %v", entries, want)
+	}
+
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("LoadFile()[%d] = %v, want %v", i, entries[i], want[i])
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("fornax explode\n"), 0o600); err != nil {
+		t.Fatalf("error writing word list: %s", err)
+	}
+
+	if _, err := LoadFile(path); err == nil {
+		t.Errorf("LoadFile() with unknown action expected error")
+	}
+}
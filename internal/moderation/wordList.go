@@ -0,0 +1,185 @@
+package moderation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const mask = "****"
+
+// WordList is a Filter backed by a list of banned words. Words match whole
+// words only, ignoring case, and the list can be changed while in use.
+type WordList struct {
+	mu    sync.RWMutex
+	words map[string]Entry
+}
+
+func NewWordList(entries []Entry) (*WordList, error) {
+	wl := &WordList{}
+	if err := wl.Replace(entries); err != nil {
+		return nil, err
+	}
+	return wl, nil
+}
+
+func (wl *WordList) Check(text string) Result {
+	wl.mu.RLock()
+	defer wl.mu.RUnlock()
+
+	result := Result{}
+	var out strings.Builder
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			out.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		word := string(runes[i:end])
+		i = end
+
+		entry, ok := wl.words[fold(word)]
+		if !ok {
+			out.WriteString(word)
+			continue
+		}
+
+		result.Matches = append(result.Matches, entry)
+
+		switch entry.Action {
+		case ActionMask:
+			out.WriteString(mask)
+		case ActionReject:
+			result.Rejected = true
+			out.WriteString(word)
+		case ActionFlag:
+			result.Flagged = true
+			out.WriteString(word)
+		}
+	}
+
+	result.Text = out.String()
+	return result
+}
+
+// Set adds a word to the list or changes the action of an existing one.
+func (wl *WordList) Set(word string, action Action) (Entry, error) {
+	entry, err := NewEntry(word, action)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	wl.words[fold(entry.Word)] = entry
+	return entry, nil
+}
+
+// Lookup returns the entry matching word, ignoring case.
+func (wl *WordList) Lookup(word string) (Entry, bool) {
+	wl.mu.RLock()
+	defer wl.mu.RUnlock()
+
+	entry, ok := wl.words[fold(word)]
+	return entry, ok
+}
+
+// Remove deletes a word from the list and reports whether it was there.
+func (wl *WordList) Remove(word string) bool {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	key := fold(word)
+	if _, ok := wl.words[key]; !ok {
+		return false
+	}
+
+	delete(wl.words, key)
+	return true
+}
+
+// Replace swaps the whole list for a new one.
+func (wl *WordList) Replace(entries []Entry) error {
+	words := make(map[string]Entry, len(entries))
+	for _, e := range entries {
+		entry, err := NewEntry(e.Word, e.Action)
+		if err != nil {
+			return err
+		}
+		words[fold(entry.Word)] = entry
+	}
+
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	wl.words = words
+	return nil
+}
+
+// Entries returns the current list sorted by word.
+func (wl *WordList) Entries() []Entry {
+	wl.mu.RLock()
+	defer wl.mu.RUnlock()
+
+	entries := make([]Entry, 0, len(wl.words))
+	for _, entry := range wl.words {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Word < entries[j].Word
+	})
+
+	return entries
+}
+
+// NewEntry validates a word and action and normalizes the word the way the
+// list stores it.
+func NewEntry(word string, action Action) (Entry, error) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return Entry{}, fmt.Errorf("empty word")
+	}
+
+	for _, r := range word {
+		if !isWordRune(r) {
+			return Entry{}, fmt.Errorf("invalid word: %q", word)
+		}
+	}
+
+	if _, err := ParseAction(string(action)); err != nil {
+		return Entry{}, err
+	}
+
+	return Entry{Word: word, Action: action}, nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// fold maps every rune to the smallest member of its case-folding orbit so
+// that words which differ only in case compare equal.
+func fold(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		min := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+		b.WriteRune(min)
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+
+	want := Cursor{
+		CreatedAt: time.Date(2024, 9, 30, 21, 12, 8, 123456000, time.UTC),
+		ID:        uuid.MustParse("bbbff1ab-2214-4f9a-a0a6-1789526c61ad"),
+	}
+
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("error decoding cursor: %s", err)
+	}
+
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Errorf("DecodeCursor() = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "Not base64", cursor: "%%%"},
+		{name: "Missing separator", cursor: "bm9waXBl"},
+		{name: "Bad time", cursor: base64.RawURLEncoding.EncodeToString([]byte("yesterday|bbbff1ab-2214-4f9a-a0a6-1789526c61ad"))},
+		{name: "Bad id", cursor: base64.RawURLEncoding.EncodeToString([]byte("2024-09-30T21:12:08Z|not-a-uuid"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.cursor); err == nil {
+				t.Errorf("DecodeCursor(%q) expected error", tt.cursor)
+			}
+		})
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      int
+		expectErr bool
+	}{
+		{name: "Empty uses default", input: "", want: DefaultLimit},
+		{name: "Valid", input: "5", want: 5},
+		{name: "Capped", input: "1000", want: MaxLimit},
+		{name: "Zero", input: "0", expectErr: true},
+		{name: "Negative", input: "-3", expectErr: true},
+		{name: "Not a number", input: "ten", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimit(tt.input)
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("ParseLimit() error = %v, wantErr %v", err, tt.expectErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      int
+		expectErr bool
+	}{
+		{name: "Empty uses zero", input: "", want: 0},
+		{name: "Valid", input: "40", want: 40},
+		{name: "Negative", input: "-1", expectErr: true},
+		{name: "Not a number", input: "forty", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOffset(tt.input)
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("ParseOffset() error = %v, wantErr %v", err, tt.expectErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseOffset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package pagination
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ParseLimit reads the limit query parameter, falling back to DefaultLimit
+// when it is empty and capping it at MaxLimit.
+func ParseLimit(s string) (int, error) {
+	if s == "" {
+		return DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing limit: %s", err)
+	}
+
+	if limit < 1 {
+		return 0, fmt.Errorf("limit must be positive")
+	}
+
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	return limit, nil
+}
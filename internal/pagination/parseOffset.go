@@ -0,0 +1,24 @@
+package pagination
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParseOffset reads the offset query parameter, defaulting to zero.
+func ParseOffset(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	offset, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing offset: %s", err)
+	}
+
+	if offset < 0 {
+		return 0, fmt.Errorf("offset must not be negative")
+	}
+
+	return offset, nil
+}
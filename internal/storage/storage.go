@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage keeps uploaded files under slash separated keys such as
+// "media/abc.png". Implementations must be safe for concurrent use.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Delete(ctx context.Context, key string) error
+	// URL is where clients can download the file stored under key.
+	URL(key string) string
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+
+	dir := t.TempDir()
+	s := NewLocalStorage(dir, "/app/assets/media/")
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "images/a.png", strings.NewReader("data")); err != nil {
+		t.Fatalf("error putting file: %s", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "images", "a.png"))
+	if err != nil {
+		t.Fatalf("error reading file: %s", err)
+	}
+	if string(got) != "data" {
+		t.Errorf("file contents = %q, want %q", got, "data")
+	}
+
+	if url := s.URL("images/a.png"); url != "/app/assets/media/images/a.png" {
+		t.Errorf("URL = %q, want %q", url, "/app/assets/media/images/a.png")
+	}
+
+	if err := s.Delete(ctx, "images/a.png"); err != nil {
+		t.Fatalf("error deleting file: %s", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "images", "a.png")); !os.IsNotExist(err) {
+		t.Errorf("file still exists after Delete")
+	}
+
+	if err := s.Delete(ctx, "images/a.png"); err != nil {
+		t.Errorf("deleting a missing file returned %s", err)
+	}
+}
+
+func TestLocalStorageInvalidKey(t *testing.T) {
+
+	s := NewLocalStorage(t.TempDir(), "/media")
+
+	for _, key := range []string{"", "..", "../escape", "/etc/passwd", "a/../../b", "a//b"} {
+		if err := s.Put(context.Background(), key, strings.NewReader("x")); err == nil {
+			t.Errorf("Put(%q) succeeded, want error", key)
+		}
+	}
+}
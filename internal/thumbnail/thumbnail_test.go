@@ -0,0 +1,57 @@
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestResizeBounds(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		maxSize       int
+		wantW, wantH  int
+	}{
+		{name: "Landscape", width: 800, height: 400, maxSize: 200, wantW: 200, wantH: 100},
+		{name: "Portrait", width: 300, height: 900, maxSize: 300, wantW: 100, wantH: 300},
+		{name: "Already small", width: 50, height: 40, maxSize: 200, wantW: 50, wantH: 40},
+		{name: "Very thin", width: 1000, height: 1, maxSize: 100, wantW: 100, wantH: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, tt.width, tt.height))
+			got := Resize(img, tt.maxSize).Bounds()
+			if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+				t.Errorf("Resize(%dx%d, %d) = %dx%d, want %dx%d",
+					tt.width, tt.height, tt.maxSize, got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestResizeAverages(t *testing.T) {
+
+	// Alternating black and white columns should average to grey
+	img := image.NewGray(image.Rect(10, 10, 14, 12))
+	for y := 10; y < 12; y++ {
+		for x := 10; x < 14; x++ {
+			if x%2 == 0 {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	got := Resize(img, 2)
+	if got.Bounds().Dx() != 2 || got.Bounds().Dy() != 1 {
+		t.Fatalf("Resize bounds = %v, want 2x1", got.Bounds())
+	}
+
+	for x := 0; x < 2; x++ {
+		c := got.RGBAAt(x, 0)
+		if c.R < 126 || c.R > 128 || c.A != 255 {
+			t.Errorf("pixel %d = %v, want mid grey", x, c)
+		}
+	}
+}
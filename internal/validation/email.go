@@ -0,0 +1,22 @@
+package validation
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// Email accepts a bare address such as user@example.com. Display names and
+// angle brackets are rejected because we store the value as given.
+func Email(value string) string {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return "must be a valid email address"
+	}
+
+	at := strings.LastIndex(value, "@")
+	if !strings.Contains(value[at+1:], ".") {
+		return "must be a valid email address"
+	}
+
+	return ""
+}
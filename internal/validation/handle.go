@@ -0,0 +1,17 @@
+package validation
+
+import (
+	"regexp"
+)
+
+var handlePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,15}$`)
+
+// Handle accepts 3 to 15 ASCII letters, digits and underscores, which is
+// what the mention parser recognises after an @.
+func Handle(value string) string {
+	if !handlePattern.MatchString(value) {
+		return "must be 3 to 15 letters, digits or underscores"
+	}
+
+	return ""
+}
@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bcrypt ignores everything past 72 bytes, so longer passwords would be
+// silently truncated.
+const maxPasswordBytes = 72
+
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:    8,
+	RequireLower: true,
+	RequireDigit: true,
+}
+
+// Password builds a rule that enforces the given policy.
+func Password(policy PasswordPolicy) Rule {
+	return func(value string) string {
+		if utf8.RuneCountInString(value) < policy.MinLength {
+			return fmt.Sprintf("must be at least %d characters", policy.MinLength)
+		}
+
+		if len(value) > maxPasswordBytes {
+			return fmt.Sprintf("must be at most %d bytes", maxPasswordBytes)
+		}
+
+		var upper, lower, digit, symbol bool
+		for _, r := range value {
+			switch {
+			case unicode.IsUpper(r):
+				upper = true
+			case unicode.IsLower(r):
+				lower = true
+			case unicode.IsDigit(r):
+				digit = true
+			case unicode.IsPunct(r) || unicode.IsSymbol(r):
+				symbol = true
+			}
+		}
+
+		switch {
+		case policy.RequireUpper && !upper:
+			return "must contain an upper case letter"
+		case policy.RequireLower && !lower:
+			return "must contain a lower case letter"
+		case policy.RequireDigit && !digit:
+			return "must contain a digit"
+		case policy.RequireSymbol && !symbol:
+			return "must contain a symbol"
+		}
+
+		return ""
+	}
+}
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Required fails on an empty string.
+func Required(value string) string {
+	if value == "" {
+		return "is required"
+	}
+
+	return ""
+}
+
+// NotBlank fails on a string that is empty or only whitespace.
+func NotBlank(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "must not be blank"
+	}
+
+	return ""
+}
+
+// MaxRunes limits length in characters rather than bytes, so accented
+// letters and emoji count once.
+func MaxRunes(n int) Rule {
+	return func(value string) string {
+		if utf8.RuneCountInString(value) > n {
+			return fmt.Sprintf("must be at most %d characters", n)
+		}
+
+		return ""
+	}
+}
+
+func MinRunes(n int) Rule {
+	return func(value string) string {
+		if utf8.RuneCountInString(value) < n {
+			return fmt.Sprintf("must be at least %d characters", n)
+		}
+
+		return ""
+	}
+}
+
+// Optional skips the remaining rules when the value is empty.
+func Optional(rules ...Rule) Rule {
+	return func(value string) string {
+		if value == "" {
+			return ""
+		}
+
+		for _, rule := range rules {
+			if msg := rule(value); msg != "" {
+				return msg
+			}
+		}
+
+		return ""
+	}
+}
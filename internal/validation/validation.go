@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"strings"
+)
+
+// Rule checks a single value and returns a message describing the problem,
+// or an empty string when the value is fine.
+type Rule func(value string) string
+
+// FieldError is a problem with one field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors collects every field that failed validation.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fe := range e {
+		messages = append(messages, fe.Field+": "+fe.Message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Check pairs a field with the rules it has to pass.
+type Check struct {
+	field string
+	value string
+	rules []Rule
+}
+
+func Field(name, value string, rules ...Rule) Check {
+	return Check{field: name, value: value, rules: rules}
+}
+
+// Validate runs every check and returns nil when they all pass. Only the
+// first failing rule of each field is reported.
+func Validate(checks ...Check) Errors {
+	var errs Errors
+
+	for _, check := range checks {
+		for _, rule := range check.rules {
+			if msg := rule(check.value); msg != "" {
+				errs = append(errs, FieldError{Field: check.field, Message: msg})
+				break
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		value   string
+		wantErr bool
+	}{
+		{name: "Required empty", rule: Required, value: "", wantErr: true},
+		{name: "Required set", rule: Required, value: "x", wantErr: false},
+		{name: "NotBlank whitespace", rule: NotBlank, value: " \t\n", wantErr: true},
+		{name: "NotBlank text", rule: NotBlank, value: " hi ", wantErr: false},
+		{name: "MaxRunes counts characters", rule: MaxRunes(3), value: "ééé", wantErr: false},
+		{name: "MaxRunes too long", rule: MaxRunes(3), value: "abcd", wantErr: true},
+		{name: "MaxRunes emoji", rule: MaxRunes(140), value: strings.Repeat("🐦", 140), wantErr: false},
+		{name: "MinRunes too short", rule: MinRunes(2), value: "a", wantErr: true},
+		{name: "Optional empty", rule: Optional(Email), value: "", wantErr: false},
+		{name: "Optional invalid", rule: Optional(Email), value: "nope", wantErr: true},
+		{name: "Email valid", rule: Email, value: "user@example.com", wantErr: false},
+		{name: "Email no at", rule: Email, value: "user.example.com", wantErr: true},
+		{name: "Email no domain dot", rule: Email, value: "user@localhost", wantErr: true},
+		{name: "Email display name", rule: Email, value: "User <user@example.com>", wantErr: true},
+		{name: "Email spaces", rule: Email, value: " user@example.com", wantErr: true},
+		{name: "Handle valid", rule: Handle, value: "chirp_fan42", wantErr: false},
+		{name: "Handle too short", rule: Handle, value: "ab", wantErr: true},
+		{name: "Handle too long", rule: Handle, value: "abcdefghijklmnop", wantErr: true},
+		{name: "Handle punctuation", rule: Handle, value: "chirp.fan", wantErr: true},
+		{name: "Handle non-ASCII", rule: Handle, value: "josé", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := tt.rule(tt.value)
+			if (msg != "") != tt.wantErr {
+				t.Errorf("rule(%q) = %q, wantErr %v", tt.value, msg, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPassword(t *testing.T) {
+	strict := PasswordPolicy{MinLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	tests := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		wantErr  bool
+	}{
+		{name: "Default valid", policy: DefaultPasswordPolicy, password: "hunter22", wantErr: false},
+		{name: "Default too short", policy: DefaultPasswordPolicy, password: "abc1", wantErr: true},
+		{name: "Default no digit", policy: DefaultPasswordPolicy, password: "password", wantErr: true},
+		{name: "Default empty", policy: DefaultPasswordPolicy, password: "", wantErr: true},
+		{name: "Too long for bcrypt", policy: DefaultPasswordPolicy, password: strings.Repeat("a1", 37), wantErr: true},
+		{name: "Strict valid", policy: strict, password: "Hunter22!x", wantErr: false},
+		{name: "Strict no symbol", policy: strict, password: "Hunter22xx", wantErr: true},
+		{name: "Strict no upper", policy: strict, password: "hunter22!x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := Password(tt.policy)(tt.password)
+			if (msg != "") != tt.wantErr {
+				t.Errorf("Password(%q) = %q, wantErr %v", tt.password, msg, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	errs := Validate(
+		Field("email", "", Required, Email),
+		Field("password", "hunter22", Required, Password(DefaultPasswordPolicy)),
+		Field("body", "  ", NotBlank, MaxRunes(140)),
+	)
+
+	if len(errs) != 2 {
+		t.Fatalf("Validate() returned %d errors, want 2: %v", len(errs), errs)
+	}
+
+	// Only the first failing rule of a field is reported
+	if errs[0].Field != "email" || errs[0].Message != "is required" {
+		t.Errorf("Validate() first error = %+v", errs[0])
+	}
+
+	if errs[1].Field != "body" {
+		t.Errorf("Validate() second error = %+v", errs[1])
+	}
+
+	if errs := Validate(Field("email", "user@example.com", Required, Email)); errs != nil {
+		t.Errorf("Validate() = %v, want nil", errs)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/lib/pq"
+)
+
+// errorCodes gives clients a stable code to switch on for each status we send
+var errorCodes = map[int]string{
+	400: "bad_request",
+	401: "unauthorized",
+	403: "forbidden",
+	404: "not_found",
+	409: "conflict",
+	500: "internal_error",
+}
+
+type errorResponse struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details"`
+}
+
+func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error marshalling JSON: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// respondWithError logs err, if any, and sends msg to the client. Server
+// errors keep err out of the response so internals don't leak.
+func respondWithError(w http.ResponseWriter, code int, msg string, err error) {
+	respondWithErrorDetails(w, code, msg, err, nil)
+}
+
+func respondWithErrorDetails(w http.ResponseWriter, code int, msg string, err error, details interface{}) {
+	if err != nil {
+		log.Printf("%s: %s", msg, err)
+	}
+
+	errorCode, ok := errorCodes[code]
+	if !ok {
+		errorCode = "error"
+	}
+
+	respondWithJSON(w, code, errorResponse{
+		Code:    errorCode,
+		Message: msg,
+		Details: details,
+	})
+}
+
+// respondWithDBError sends a 404 when the query found nothing and a 500 for
+// anything else.
+func respondWithDBError(w http.ResponseWriter, notFoundMsg string, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, 404, notFoundMsg, err)
+		return
+	}
+
+	respondWithError(w, 500, "Something went wrong", err)
+}
+
+// uniqueViolation reports whether err is a Postgres unique violation, along
+// with the name of the constraint that was hit.
+func uniqueViolation(err error) (string, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return pqErr.Constraint, true
+	}
+	return "", false
+}
+
+// authRealm names us in WWW-Authenticate challenges
+const authRealm = "chirpy"
+
+// respondWithAuthError sends a 401 for a missing or rejected access token,
+// with a WWW-Authenticate header telling the client which it was, so it
+// knows whether to refresh or log in again.
+func respondWithAuthError(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", auth.WWWAuthenticate(authRealm, err))
+
+	msg := "Invalid bearer token"
+	switch {
+	case errors.Is(err, auth.ErrNoBearerToken):
+		msg = "Missing bearer token"
+	case errors.Is(err, auth.ErrTokenExpired):
+		msg = "Access token expired"
+	}
+
+	respondWithError(w, 401, msg, err)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+)
+
+// loadJWTKeys builds the access token key set from the environment.
+// JWT_SIGNING_KEY_FILE is the RSA or Ed25519 private key new tokens are
+// signed with. JWT_VERIFY_KEY_FILES lists keys, comma separated, that are
+// still accepted after a rotation. Without a signing key, tokens fall back to
+// HS256 with TOKEN_STRING. Once a signing key is set, HS256 tokens are refused
+// unless JWT_ACCEPT_HS256_UNTIL gives an RFC 3339 time to keep accepting them
+// until. JWT_LEEWAY is how much clock skew to allow when checking expiry.
+func loadJWTKeys(tokenSecret string) (*auth.KeySet, error) {
+
+	keys := auth.NewKeySet(tokenSecret)
+
+	leeway := 30 * time.Second
+	if value := os.Getenv("JWT_LEEWAY"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing JWT_LEEWAY: %s", err)
+		}
+		leeway = parsed
+	}
+	keys.SetLeeway(leeway)
+
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFY_KEY_FILES"), ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %s", path, err)
+		}
+
+		key, err := auth.ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		if _, err := keys.AddVerificationKey(key); err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+	}
+
+	if path := os.Getenv("JWT_SIGNING_KEY_FILE"); path != "" {
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %s", path, err)
+		}
+
+		key, err := auth.ParsePrivateKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		kid, err := keys.AddSigningKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		log.Printf("Signing access tokens with key %s", kid)
+
+		if value := os.Getenv("JWT_ACCEPT_HS256_UNTIL"); value != "" {
+			if tokenSecret == "" {
+				return nil, fmt.Errorf("JWT_ACCEPT_HS256_UNTIL needs TOKEN_STRING")
+			}
+
+			until, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing JWT_ACCEPT_HS256_UNTIL: %s", err)
+			}
+
+			keys.AcceptHS256Until(until)
+			if time.Now().Before(until) {
+				log.Printf("Accepting HS256 access tokens until %s", until.Format(time.RFC3339))
+			}
+		}
+	} else if tokenSecret == "" {
+		return nil, fmt.Errorf("set JWT_SIGNING_KEY_FILE or TOKEN_STRING")
+	}
+
+	return keys, nil
+}
+
+func (cfg *apiConfig) handlerJWKS(w http.ResponseWriter, r *http.Request) {
+
+	// Keep the cache short so verifiers pick up a new key soon after it is
+	// added
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	respondWithJSON(w, 200, cfg.jwtKeys.JWKS())
+}
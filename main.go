@@ -1,29 +1,51 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
-	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/IsahiRea/chirp/internal/auth"
 	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/hashtags"
+	"github.com/IsahiRea/chirp/internal/mailer"
+	"github.com/IsahiRea/chirp/internal/moderation"
+	"github.com/IsahiRea/chirp/internal/pagination"
+	"github.com/IsahiRea/chirp/internal/storage"
+	"github.com/IsahiRea/chirp/internal/validation"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
 type apiConfig struct {
-	fileserverHits atomic.Int32
-	dbQueries      *database.Queries
-	platform       string
-	tokenSecret    string
-	polkaKey       string
+	fileserverHits   atomic.Int32
+	db               *sql.DB
+	dbQueries        *database.Queries
+	platform         string
+	emailTokenSecret string
+	jwtKeys          *auth.KeySet
+	polkaKey         string
+
+	mailer mailer.Mailer
+
+	chirpFilter        moderation.Filter
+	bannedWords        *moderation.WordList
+	persistBannedWords bool
+
+	trending *trendingCache
+
+	resetThrottle *resetThrottle
+
+	storage storage.Storage
 }
 
 func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
@@ -42,22 +64,28 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 
 	userReq := recieve{}
 	if err := json.NewDecoder(r.Body).Decode(&userReq); err != nil {
-		log.Printf("Error decoding parameters: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 400, "Couldn't decode parameters", err)
 		return
 	}
 
 	user, err := cfg.dbQueries.GetHashPassByEmail(r.Context(), userReq.Email)
-
 	if err != nil {
-		log.Printf("Error finding user: %s", err)
-		w.WriteHeader(500)
+		// Unknown emails get the same answer as wrong passwords
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, 401, "Incorrect email or password", err)
+			return
+		}
+		respondWithError(w, 500, "Couldn't find user", err)
 		return
 	}
 
 	if err := auth.CheckPasswordHash(userReq.Password, user.HashedPassword); err != nil {
-		log.Printf("Error email or password: %s", err)
-		w.WriteHeader(401)
+		respondWithError(w, 401, "Incorrect email or password", err)
+		return
+	}
+
+	if user.SuspendedAt.Valid {
+		respondWithError(w, 403, "Account is suspended", nil)
 		return
 	}
 
@@ -65,34 +93,19 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 
 	timeDurationJWT, err := time.ParseDuration("1h")
 	if err != nil {
-		log.Printf("Error parsing time duration: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't parse token duration", err)
 		return
 	}
 
-	token, err := auth.MakeJWT(user.ID, cfg.tokenSecret, timeDurationJWT)
+	token, err := auth.MakeJWT(user.ID, user.Role, cfg.jwtKeys, timeDurationJWT)
 	if err != nil {
-		log.Printf("Error creating JWT: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't create access token", err)
 		return
 	}
 
-	refreshToken, err := auth.MakeRefreshToken()
+	refreshToken, err := createRefreshToken(r.Context(), cfg.dbQueries, user.ID, uuid.New(), sql.NullString{}, requestDevice(r))
 	if err != nil {
-		log.Printf("Error creating refresh token: %s", err)
-		w.WriteHeader(500)
-		return
-	}
-
-	tokenReq := database.CreateRefeshTokenParams{
-		Token:     refreshToken,
-		UserID:    user.ID,
-		ExpiresAt: time.Now().AddDate(0, 0, 60),
-	}
-
-	if err := cfg.dbQueries.CreateRefeshToken(r.Context(), tokenReq); err != nil {
-		log.Printf("Error saving refresh token: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't create refresh token", err)
 		return
 	}
 
@@ -102,6 +115,10 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt time.Time `json:"updated_at"`
 		Email     string    `json:"email"`
 		Premium   bool      `json:"is_chirp_red"`
+		Handle    string    `json:"handle"`
+		Name      string    `json:"display_name"`
+		Role      string    `json:"role"`
+		Verified  bool      `json:"email_verified"`
 		Token     string    `json:"token"`
 		RToken    string    `json:"refresh_token"`
 	}{
@@ -110,88 +127,135 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 		user.UpdatedAt,
 		user.Email,
 		user.IsChirpyRed,
+		user.Handle.String,
+		user.DisplayName,
+		user.Role,
+		user.EmailVerifiedAt.Valid,
 		token,
 		refreshToken,
 	}
 
-	data, err := json.Marshal(sendBack)
-	if err != nil {
-		log.Printf("Error during marshal: %s", err)
-		w.WriteHeader(500)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
-	w.Write(data)
+	respondWithJSON(w, 200, sendBack)
 }
 
 func (cfg *apiConfig) handlerRefresh(w http.ResponseWriter, r *http.Request) {
 
 	token, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		log.Printf("Error obtaining refresh token: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 401, "Missing refresh token", err)
 		return
 	}
 
-	user, err := cfg.dbQueries.GetUserFromRToken(r.Context(), token)
+	tokenHash := auth.HashToken(token)
+
+	user, err := cfg.dbQueries.GetUserFromRToken(r.Context(), tokenHash)
 	if err != nil {
-		log.Printf("Error saving refresh token: %s", err)
-		w.WriteHeader(401)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, 401, "Invalid refresh token", err)
+			return
+		}
+		respondWithError(w, 500, "Couldn't find refresh token", err)
 		return
 	}
 
-	if time.Now().After(user.ExpiresAt) || user.RevokedAt.Valid {
-		log.Printf("Error token expired: %s", err)
-		w.WriteHeader(401)
+	// Every refresh token is only good for one use. Seeing a used one again
+	// means it was copied, so end the whole session for both parties
+	if user.RevokedAt.Valid {
+		cfg.revokeTokenFamily(r.Context(), user.FamilyID)
+		respondWithError(w, 401, "Refresh token expired or revoked", nil)
+		return
+	}
+
+	if time.Now().After(user.ExpiresAt) {
+		respondWithError(w, 401, "Refresh token expired or revoked", nil)
+		return
+	}
+
+	// Read the role again so role changes apply from the next refresh
+	dbUser, err := cfg.dbQueries.GetUserByID(r.Context(), user.UserID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't find user", err)
 		return
 	}
 
 	timeDurationJWT, err := time.ParseDuration("1h")
 	if err != nil {
-		log.Printf("Error parsing time duration: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't parse token duration", err)
 		return
 	}
 
-	newAccessToken, err := auth.MakeJWT(user.UserID, cfg.tokenSecret, timeDurationJWT)
+	newAccessToken, err := auth.MakeJWT(user.UserID, dbUser.Role, cfg.jwtKeys, timeDurationJWT)
 	if err != nil {
-		log.Printf("Error creaing JWT: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't create access token", err)
 		return
 	}
 
-	sendBack := struct {
-		Token string `json:"token"`
-	}{
-		newAccessToken,
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't start transaction", err)
+		return
 	}
+	defer tx.Rollback()
 
-	data, err := json.Marshal(sendBack)
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	rotated, err := qtx.RotateRefreshToken(r.Context(), tokenHash)
 	if err != nil {
-		log.Printf("Error during marshal: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't revoke refresh token", err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
-	w.Write(data)
+	// Another request used the token between our read and this update
+	if rotated == 0 {
+		tx.Rollback()
+		cfg.revokeTokenFamily(r.Context(), user.FamilyID)
+		respondWithError(w, 401, "Refresh token expired or revoked", nil)
+		return
+	}
+
+	newRefreshToken, err := createRefreshToken(r.Context(), qtx, user.UserID, user.FamilyID, sql.NullString{String: tokenHash, Valid: true}, requestDevice(r))
+	if err != nil {
+		respondWithError(w, 500, "Couldn't create refresh token", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, 500, "Couldn't commit refresh token", err)
+		return
+	}
+
+	sendBack := struct {
+		Token  string `json:"token"`
+		RToken string `json:"refresh_token"`
+	}{
+		newAccessToken,
+		newRefreshToken,
+	}
+
+	respondWithJSON(w, 200, sendBack)
 }
 
 func (cfg *apiConfig) handlerRevoke(w http.ResponseWriter, r *http.Request) {
 
 	token, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		log.Printf("Error obtaining refresh token: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 401, "Missing refresh token", err)
+		return
+	}
+
+	// Revoking any token in a family logs that session out entirely
+	stored, err := cfg.dbQueries.GetUserFromRToken(r.Context(), auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			w.WriteHeader(204)
+			return
+		}
+		respondWithError(w, 500, "Couldn't find refresh token", err)
 		return
 	}
 
-	if err := cfg.dbQueries.RevokeRefreshToken(r.Context(), token); err != nil {
-		log.Printf("Error revoking refresh token: %s", err)
-		w.WriteHeader(401)
+	if _, err := cfg.dbQueries.RevokeRefreshTokenFamily(r.Context(), stored.FamilyID); err != nil {
+		respondWithError(w, 500, "Couldn't revoke refresh token", err)
 		return
 	}
 
@@ -200,6 +264,8 @@ func (cfg *apiConfig) handlerRevoke(w http.ResponseWriter, r *http.Request) {
 
 //--------------------------------------------------------------------------------
 
+const maxDisplayNameLength = 50
+
 func (cfg *apiConfig) handlerUsers(w http.ResponseWriter, r *http.Request) {
 
 	type recieve struct {
@@ -209,15 +275,21 @@ func (cfg *apiConfig) handlerUsers(w http.ResponseWriter, r *http.Request) {
 
 	userReq := recieve{}
 	if err := json.NewDecoder(r.Body).Decode(&userReq); err != nil {
-		log.Printf("Error decoding parameters: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 400, "Couldn't decode parameters", err)
+		return
+	}
+
+	if errs := validation.Validate(
+		validation.Field("email", userReq.Email, validation.Required, validation.Email),
+		validation.Field("password", userReq.Password, validation.Required, validation.Password(validation.DefaultPasswordPolicy)),
+	); errs != nil {
+		respondWithErrorDetails(w, 400, "Invalid user", nil, errs)
 		return
 	}
 
 	hashedPassword, err := auth.HashPassword(userReq.Password)
 	if err != nil {
-		log.Printf("Error hashing password: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't hash password", err)
 		return
 	}
 
@@ -228,186 +300,452 @@ func (cfg *apiConfig) handlerUsers(w http.ResponseWriter, r *http.Request) {
 
 	user, err := cfg.dbQueries.CreateUser(r.Context(), requestDataSend)
 	if err != nil {
-		log.Printf("Error finding user: %s", err)
-		w.WriteHeader(500)
+		if _, ok := uniqueViolation(err); ok {
+			respondWithError(w, 409, "Email is already in use", err)
+			return
+		}
+		respondWithError(w, 500, "Couldn't create user", err)
 		return
 	}
 
+	// The account exists either way, and the user can ask for another email
+	if err := cfg.sendVerificationEmail(r.Context(), user.ID, user.Email); err != nil {
+		log.Printf("Error sending verification email to user %s: %s", user.ID, err)
+	}
+
 	sendBack := struct {
-		ID        uuid.UUID `json:"id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
-		Email     string    `json:"email"`
-		Premium   bool      `json:"is_chirp_red"`
+		ID            uuid.UUID `json:"id"`
+		CreatedAt     time.Time `json:"created_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
+		Email         string    `json:"email"`
+		Premium       bool      `json:"is_chirp_red"`
+		EmailVerified bool      `json:"email_verified"`
 	}{
 		user.ID,
 		user.CreatedAt,
 		user.UpdatedAt,
 		user.Email,
 		user.IsChirpyRed,
+		user.EmailVerifiedAt.Valid,
 	}
 
-	data, err := json.Marshal(sendBack)
-	if err != nil {
-		log.Printf("Error during marshal: %s", err)
-		w.WriteHeader(500)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
-	w.Write(data)
+	respondWithJSON(w, 201, sendBack)
 }
 
 func (cfg *apiConfig) handlerUsersUpdate(w http.ResponseWriter, r *http.Request) {
 
 	tokenString, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		log.Printf("Error obtaining token: %s", err)
-		w.WriteHeader(500)
+		respondWithAuthError(w, err)
 		return
 	}
 
-	id, err := auth.ValidateJWT(tokenString, cfg.tokenSecret)
+	id, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
 	if err != nil {
-		log.Printf("Error validating token: %s", err)
-		w.WriteHeader(401)
+		respondWithAuthError(w, err)
 		return
 	}
 
+	// Every field is optional; only the ones sent are changed
 	type recieve struct {
-		Password string `json:"password"`
-		Email    string `json:"email"`
+		Email           *string `json:"email"`
+		Password        *string `json:"password"`
+		DisplayName     *string `json:"display_name"`
+		Bio             *string `json:"bio"`
+		Handle          *string `json:"handle"`
+		CurrentPassword string  `json:"current_password"`
 	}
 
 	requestData := recieve{}
 	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
-		log.Printf("Error decoding parameters: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 400, "Couldn't decode parameters", err)
 		return
 	}
 
-	user, err := cfg.dbQueries.GetHashPassByEmail(r.Context(), requestData.Email)
-	if err != nil {
-		log.Printf("Error obtaining users: %s", err)
-		w.WriteHeader(500)
+	if requestData.Email == nil && requestData.Password == nil && requestData.DisplayName == nil && requestData.Bio == nil && requestData.Handle == nil {
+		respondWithError(w, 400, "Nothing to update", nil)
 		return
 	}
 
-	if user.ID != id {
-		log.Printf("Error unauthorized user: %s", err)
-		w.WriteHeader(401)
+	var checks []validation.Check
+	if requestData.Email != nil {
+		checks = append(checks, validation.Field("email", *requestData.Email, validation.Required, validation.Email))
+	}
+	if requestData.Password != nil {
+		checks = append(checks, validation.Field("password", *requestData.Password, validation.Required, validation.Password(validation.DefaultPasswordPolicy)))
+	}
+	if requestData.DisplayName != nil {
+		checks = append(checks, validation.Field("display_name", *requestData.DisplayName, validation.MaxRunes(maxDisplayNameLength)))
+	}
+	if requestData.Bio != nil {
+		checks = append(checks, validation.Field("bio", *requestData.Bio, validation.MaxRunes(maxBioLength)))
+	}
+	if requestData.Handle != nil {
+		checks = append(checks, validation.Field("handle", *requestData.Handle, validation.Handle))
+	}
+
+	if errs := validation.Validate(checks...); errs != nil {
+		respondWithErrorDetails(w, 400, "Invalid user", nil, errs)
 		return
 	}
 
-	newHashPass, err := auth.HashPassword(requestData.Password)
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), id)
 	if err != nil {
-		log.Printf("Error hashing password: %s", err)
-		w.WriteHeader(500)
+		respondWithDBError(w, "User not found", err)
 		return
 	}
 
-	sendData := database.UpdatePasswordParams{
-		ID:             id,
-		HashedPassword: newHashPass,
+	sendData := database.UpdateUserParams{ID: id}
+
+	emailChanged := requestData.Email != nil && *requestData.Email != user.Email
+	passwordChanged := requestData.Password != nil
+
+	// A stolen access token alone shouldn't be enough to take over the account
+	if emailChanged || passwordChanged {
+
+		if errs := validation.Validate(
+			validation.Field("current_password", requestData.CurrentPassword, validation.Required),
+		); errs != nil {
+			respondWithErrorDetails(w, 400, "Changing email or password requires your current password", nil, errs)
+			return
+		}
+
+		if err := auth.CheckPasswordHash(requestData.CurrentPassword, user.HashedPassword); err != nil {
+			respondWithError(w, 403, "Incorrect current password", err)
+			return
+		}
+	}
+
+	if emailChanged {
+		sendData.Email = sql.NullString{String: *requestData.Email, Valid: true}
 	}
 
-	newUser, err := cfg.dbQueries.UpdatePassword(r.Context(), sendData)
+	if passwordChanged {
+		newHashPass, err := auth.HashPassword(*requestData.Password)
+		if err != nil {
+			respondWithError(w, 500, "Couldn't hash password", err)
+			return
+		}
+
+		sendData.HashedPassword = sql.NullString{String: newHashPass, Valid: true}
+	}
+
+	if requestData.DisplayName != nil {
+		sendData.DisplayName = sql.NullString{String: *requestData.DisplayName, Valid: true}
+	}
+
+	if requestData.Bio != nil {
+		sendData.Bio = sql.NullString{String: *requestData.Bio, Valid: true}
+	}
+
+	if requestData.Handle != nil {
+		sendData.Handle = sql.NullString{String: *requestData.Handle, Valid: true}
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
 	if err != nil {
-		log.Printf("Error updating password: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't start transaction", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	newUser, err := qtx.UpdateUser(r.Context(), sendData)
+	if err != nil {
+		if constraint, ok := uniqueViolation(err); ok {
+			if constraint == "users_handle_lower_key" {
+				respondWithError(w, 409, "Handle is already taken", err)
+				return
+			}
+			respondWithError(w, 409, "Email is already in use", err)
+			return
+		}
+		respondWithError(w, 500, "Couldn't update user", err)
 		return
 	}
 
+	// Sessions signed in with the old password shouldn't outlive it
+	if passwordChanged {
+		if err := qtx.RevokeUserRefreshTokens(r.Context(), id); err != nil {
+			respondWithError(w, 500, "Couldn't revoke refresh tokens", err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, 500, "Couldn't commit user update", err)
+		return
+	}
+
+	if emailChanged {
+		if err := cfg.sendVerificationEmail(r.Context(), newUser.ID, newUser.Email); err != nil {
+			log.Printf("Error sending verification email to user %s: %s", newUser.ID, err)
+		}
+	}
+
 	sendBack := struct {
-		ID        uuid.UUID `json:"id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
-		Email     string    `json:"email"`
-		Premium   bool      `json:"is_chirp_red"`
+		ID            uuid.UUID `json:"id"`
+		CreatedAt     time.Time `json:"created_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
+		Email         string    `json:"email"`
+		Premium       bool      `json:"is_chirp_red"`
+		Handle        string    `json:"handle"`
+		DisplayName   string    `json:"display_name"`
+		Bio           string    `json:"bio"`
+		AvatarURL     string    `json:"avatar_url"`
+		EmailVerified bool      `json:"email_verified"`
 	}{
 		newUser.ID,
 		newUser.CreatedAt,
 		newUser.UpdatedAt,
 		newUser.Email,
 		newUser.IsChirpyRed,
+		newUser.Handle.String,
+		newUser.DisplayName,
+		newUser.Bio,
+		newUser.AvatarUrl,
+		newUser.EmailVerifiedAt.Valid,
 	}
 
-	data, err := json.Marshal(sendBack)
-	if err != nil {
-		log.Printf("Error marshalling JSON: %s", err)
-		w.WriteHeader(500)
-		return
+	respondWithJSON(w, 200, sendBack)
+}
+
+//--------------------------------------------------------------------------------
+
+type Chirp struct {
+	ID            uuid.UUID     `json:"id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	Body          string        `json:"body"`
+	UserID        uuid.UUID     `json:"user_id"`
+	ParentID      uuid.NullUUID `json:"parent_id"`
+	QuotedChirpID uuid.NullUUID `json:"quoted_chirp_id"`
+	LikeCount     int64         `json:"like_count"`
+	LikedByMe     bool          `json:"liked_by_me"`
+	RechirpedBy   *uuid.UUID    `json:"rechirped_by,omitempty"`
+	RechirpedAt   *time.Time    `json:"rechirped_at,omitempty"`
+	Author        *Author       `json:"author,omitempty"`
+	Media         []Media       `json:"media,omitempty"`
+}
+
+func databaseChirpToChirp(chirp database.Chirp) Chirp {
+	return Chirp{
+		ID:            chirp.ID,
+		CreatedAt:     chirp.CreatedAt,
+		UpdatedAt:     chirp.UpdatedAt,
+		Body:          chirp.Body,
+		UserID:        chirp.UserID,
+		ParentID:      chirp.ParentID,
+		QuotedChirpID: chirp.QuotedChirpID,
+	}
+}
+
+func databaseChirpsToChirps(dbChirps []database.Chirp) []Chirp {
+	chirps := make([]Chirp, 0, len(dbChirps))
+	for _, chirp := range dbChirps {
+		chirps = append(chirps, databaseChirpToChirp(chirp))
 	}
+	return chirps
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
-	w.Write(data)
+// userChirpRowToChirp converts a row from an author listing, which mixes the
+// author's own chirps with the ones they rechirped.
+func userChirpRowToChirp(userID uuid.UUID, row database.GetChirpsByUserIDPageDescRow) Chirp {
+	chirp := Chirp{
+		ID:            row.ID,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+		Body:          row.Body,
+		UserID:        row.UserID,
+		ParentID:      row.ParentID,
+		QuotedChirpID: row.QuotedChirpID,
+	}
 
+	if row.RechirpedAt.Valid {
+		chirp.RechirpedBy = &userID
+		chirp.RechirpedAt = &row.RechirpedAt.Time
+	}
+
+	return chirp
 }
 
-//--------------------------------------------------------------------------------
+type chirpsPage struct {
+	Chirps     []Chirp `json:"chirps"`
+	NextCursor string  `json:"next_cursor"`
+}
+
+// newChirpsPage drops the extra row the page queries fetch as a look-ahead
+// and derives the next cursor from the last chirp that is kept.
+func newChirpsPage(chirps []Chirp, limit int) chirpsPage {
+
+	nextCursor := ""
+	if len(chirps) > limit {
+		chirps = chirps[:limit]
+		last := chirps[len(chirps)-1]
+
+		// Rechirps are listed by when they were shared, not when the chirp was written
+		listedAt := last.CreatedAt
+		if last.RechirpedAt != nil {
+			listedAt = *last.RechirpedAt
+		}
+
+		nextCursor = pagination.EncodeCursor(pagination.Cursor{CreatedAt: listedAt, ID: last.ID})
+	}
+
+	return chirpsPage{Chirps: chirps, NextCursor: nextCursor}
+}
+
+// parseCursor decodes the cursor query parameter into the nullable values
+// the page queries expect. An empty cursor starts from the first page.
+func parseCursor(cursorString string) (sql.NullTime, uuid.NullUUID, error) {
+	if cursorString == "" {
+		return sql.NullTime{}, uuid.NullUUID{}, nil
+	}
+
+	cursor, err := pagination.DecodeCursor(cursorString)
+	if err != nil {
+		return sql.NullTime{}, uuid.NullUUID{}, err
+	}
+
+	return sql.NullTime{Time: cursor.CreatedAt, Valid: true}, uuid.NullUUID{UUID: cursor.ID, Valid: true}, nil
+}
+
+// getChirpsPage fetches up to limit chirps after the cursor, optionally
+// restricted to a single author's chirps and rechirps.
+func (cfg *apiConfig) getChirpsPage(ctx context.Context, authorID uuid.NullUUID, sortBy string, cursorCreatedAt sql.NullTime, cursorID uuid.NullUUID, limit int32) ([]Chirp, error) {
+
+	if authorID.Valid {
+
+		var rows []database.GetChirpsByUserIDPageDescRow
+
+		if sortBy == "desc" {
+			descRows, err := cfg.dbQueries.GetChirpsByUserIDPageDesc(ctx, database.GetChirpsByUserIDPageDescParams{
+				UserID:          authorID.UUID,
+				CursorCreatedAt: cursorCreatedAt,
+				CursorID:        cursorID,
+				PageLimit:       limit,
+			})
+			if err != nil {
+				return nil, err
+			}
+			rows = descRows
+		} else {
+			ascRows, err := cfg.dbQueries.GetChirpsByUserIDPageAsc(ctx, database.GetChirpsByUserIDPageAscParams{
+				UserID:          authorID.UUID,
+				CursorCreatedAt: cursorCreatedAt,
+				CursorID:        cursorID,
+				PageLimit:       limit,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, row := range ascRows {
+				rows = append(rows, database.GetChirpsByUserIDPageDescRow(row))
+			}
+		}
+
+		chirps := make([]Chirp, 0, len(rows))
+		for _, row := range rows {
+			chirps = append(chirps, userChirpRowToChirp(authorID.UUID, row))
+		}
+		return chirps, nil
+	}
+
+	var dbChirps []database.Chirp
+	var err error
+
+	if sortBy == "desc" {
+		dbChirps, err = cfg.dbQueries.GetChirpsPageDesc(ctx, database.GetChirpsPageDescParams{
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			PageLimit:       limit,
+		})
+	} else {
+		dbChirps, err = cfg.dbQueries.GetChirpsPageAsc(ctx, database.GetChirpsPageAscParams{
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        cursorID,
+			PageLimit:       limit,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return databaseChirpsToChirps(dbChirps), nil
+}
 
 func (cfg *apiConfig) handlerGetChirps(w http.ResponseWriter, r *http.Request) {
 
 	authorID := r.URL.Query().Get("author_id")
+	tag := hashtags.Normalize(r.URL.Query().Get("tag"))
 	sortBy := r.URL.Query().Get("sort")
 
 	if sortBy == "" {
 		sortBy = "asc"
 	}
 
+	if sortBy != "asc" && sortBy != "desc" {
+		respondWithError(w, 400, "Sort must be asc or desc", nil)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid limit", err)
+		return
+	}
+
+	cursorCreatedAt, cursorID, err := parseCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid cursor", err)
+		return
+	}
+
+	author := uuid.NullUUID{}
 	if authorID != "" {
 
 		id, err := uuid.Parse(authorID)
 		if err != nil {
-			log.Printf("Invalid ID: %s", err)
-			w.WriteHeader(404)
+			respondWithError(w, 400, "Invalid author ID", err)
 			return
 		}
 
-		sendData := database.GetChirpsByUserIDParams{
-			UserID:  id,
-			Column2: sortBy,
-		}
+		author = uuid.NullUUID{UUID: id, Valid: true}
+	}
 
-		chirpsFromAuthor, err := cfg.dbQueries.GetChirpsByUserID(r.Context(), sendData)
-		if err != nil {
-			log.Printf("Error obtaining chirps: %s", err)
-			w.WriteHeader(404)
-			return
-		}
+	// Ask for one extra row so we know whether another page exists
+	var chirps []Chirp
+	if tag != "" {
+		chirps, err = cfg.getChirpsByTagPage(r.Context(), tag, author, sortBy, cursorCreatedAt, cursorID, int32(limit+1))
+	} else {
+		chirps, err = cfg.getChirpsPage(r.Context(), author, sortBy, cursorCreatedAt, cursorID, int32(limit+1))
+	}
+	if err != nil {
+		respondWithError(w, 500, "Couldn't get chirps", err)
+		return
+	}
 
-		data, err := json.Marshal(chirpsFromAuthor)
-		if err != nil {
-			log.Printf("Error Marshalling all chirps: %s", err)
-			w.WriteHeader(500)
-			return
-		}
+	sendBack := newChirpsPage(chirps, limit)
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(200)
-		w.Write(data)
+	if err := cfg.addLikeStats(r.Context(), chirpRefs(sendBack.Chirps), cfg.viewerID(r)); err != nil {
+		respondWithError(w, 500, "Couldn't get like counts", err)
 		return
 	}
 
-	allChirps, err := cfg.dbQueries.GetAllChirps(r.Context(), sortBy)
-	if err != nil {
-		log.Printf("Error obtaining chirps: %s", err)
-		w.WriteHeader(500)
+	if err := cfg.addMedia(r.Context(), chirpRefs(sendBack.Chirps)); err != nil {
+		respondWithError(w, 500, "Couldn't get chirp media", err)
 		return
 	}
 
-	data, err := json.Marshal(allChirps)
-	if err != nil {
-		log.Printf("Error Marshalling all chirps: %s", err)
-		w.WriteHeader(500)
-		return
+	if embedsAuthor(r) {
+		if err := cfg.addAuthors(r.Context(), chirpRefs(sendBack.Chirps)); err != nil {
+			respondWithError(w, 500, "Couldn't get chirp authors", err)
+			return
+		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
-	w.Write(data)
+	respondWithJSON(w, 200, sendBack)
 }
 
 func (cfg *apiConfig) handlerGetChirpID(w http.ResponseWriter, r *http.Request) {
@@ -415,129 +753,232 @@ func (cfg *apiConfig) handlerGetChirpID(w http.ResponseWriter, r *http.Request)
 
 	id, err := uuid.Parse(uuidString)
 	if err != nil {
-		log.Println("Invalid resource")
-		w.WriteHeader(404)
+		respondWithError(w, 404, "Chirp not found", err)
 		return
 	}
 
 	chirp, err := cfg.dbQueries.GetChirpByID(r.Context(), id)
 	if err != nil {
-		log.Printf("Error finding chirp by ID: %s", err)
-		w.WriteHeader(404)
+		respondWithDBError(w, "Chirp not found", err)
 		return
 	}
 
-	data, err := json.Marshal(chirp)
-	if err != nil {
-		log.Printf("Error Marshalling chirp by ID: %s", err)
-		w.WriteHeader(500)
+	sendBack := databaseChirpToChirp(chirp)
+
+	if err := cfg.addLikeStats(r.Context(), []*Chirp{&sendBack}, cfg.viewerID(r)); err != nil {
+		respondWithError(w, 500, "Couldn't get like counts", err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
-	w.Write(data)
+	if err := cfg.addMedia(r.Context(), []*Chirp{&sendBack}); err != nil {
+		respondWithError(w, 500, "Couldn't get chirp media", err)
+		return
+	}
+
+	if embedsAuthor(r) {
+		if err := cfg.addAuthors(r.Context(), []*Chirp{&sendBack}); err != nil {
+			respondWithError(w, 500, "Couldn't get chirp authors", err)
+			return
+		}
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
+
+const maxChirpLength = 140
+
+// validateChirpBody holds the rules every chirp body has to pass, whether it
+// is being created or edited.
+func validateChirpBody(body string) validation.Errors {
+	return validation.Validate(
+		validation.Field("body", body, validation.NotBlank, validation.MaxRunes(maxChirpLength)),
+	)
+}
+
+// moderateChirpBody runs a valid chirp body through the word filter before
+// it is stored.
+func (cfg *apiConfig) moderateChirpBody(body string) (moderation.Result, error) {
+
+	result := cfg.chirpFilter.Check(body)
+	if result.Rejected {
+		return result, fmt.Errorf("Chirp contains banned words")
+	}
+
+	return result, nil
 }
 
 func (cfg *apiConfig) handlerChirps(w http.ResponseWriter, r *http.Request) {
 
 	tokenString, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		log.Printf("Error obtaining token: %s", err)
-		w.WriteHeader(500)
+		respondWithAuthError(w, err)
 		return
 	}
 
-	id, err := auth.ValidateJWT(tokenString, cfg.tokenSecret)
+	id, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
 	if err != nil {
-		log.Printf("Error validating token: %s", err)
-		w.WriteHeader(401)
+		respondWithAuthError(w, err)
 		return
 	}
 
-	type errors struct {
-		ErrorMsg string `json:"error"`
-	}
-
 	type recieve struct {
-		Body   string    `json:"body"`
-		UserID uuid.UUID `json:"user_id"`
+		Body         string        `json:"body"`
+		UserID       uuid.UUID     `json:"user_id"`
+		InReplyTo    uuid.NullUUID `json:"in_reply_to"`
+		QuoteChirpID uuid.NullUUID `json:"quote_chirp_id"`
+		MediaIDs     []uuid.UUID   `json:"media_ids"`
 	}
 
 	requestData := recieve{}
 	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
-
-		log.Printf("Error decoding parameters: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 400, "Couldn't decode parameters", err)
 		return
 	}
 
 	if requestData.UserID != id {
-		log.Printf("Error Unauthorized: %s", err)
-		w.WriteHeader(401)
+		respondWithError(w, 403, "You can only chirp as yourself", nil)
 		return
 	}
 
-	if len(requestData.Body) > 140 {
+	blocked, err := cfg.checkCanPost(r.Context(), id)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't find user", err)
+		return
+	}
 
-		errorsResp := errors{ErrorMsg: "Chirp is too long"}
-		data, err := json.Marshal(&errorsResp)
-		if err != nil {
-			log.Printf("Error marshalling JSON: %s", err)
-			w.WriteHeader(500)
+	if blocked != "" {
+		respondWithError(w, 403, blocked, nil)
+		return
+	}
+
+	if errs := validateChirpBody(requestData.Body); errs != nil {
+		respondWithErrorDetails(w, 400, "Invalid chirp", nil, errs)
+		return
+	}
+
+	moderated, err := cfg.moderateChirpBody(requestData.Body)
+	if err != nil {
+		respondWithError(w, 400, err.Error(), nil)
+		return
+	}
+
+	requestData.Body = moderated.Text
+
+	if requestData.InReplyTo.Valid {
+		if _, err := cfg.dbQueries.GetChirpByID(r.Context(), requestData.InReplyTo.UUID); err != nil {
+			respondWithDBError(w, "Parent chirp not found", err)
 			return
 		}
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(400)
-		w.Write(data)
-		return
+	// The quoted chirp is referenced, not copied, so only the quote text counts towards the limit
+	if requestData.QuoteChirpID.Valid {
+		if _, err := cfg.dbQueries.GetChirpByID(r.Context(), requestData.QuoteChirpID.UUID); err != nil {
+			respondWithDBError(w, "Quoted chirp not found", err)
+			return
+		}
 	}
 
-	// Profane
-	bannedWords := []string{"kerfuffle", "sharbert", "fornax", "Kerfuffle", "Sharbert", "Fornax"}
+	if len(requestData.MediaIDs) > maxChirpMedia {
+		respondWithError(w, 400, "A chirp can have at most 4 media attachments", nil)
+		return
+	}
 
-	for _, word := range bannedWords {
-		requestData.Body = strings.ReplaceAll(requestData.Body, word, "****")
+	seenMedia := make(map[uuid.UUID]bool, len(requestData.MediaIDs))
+	for _, mediaID := range requestData.MediaIDs {
+		if seenMedia[mediaID] {
+			respondWithError(w, 400, "Duplicate media ID", nil)
+			return
+		}
+		seenMedia[mediaID] = true
 	}
 
 	requestDataSend := database.CreateChirpParams{
-		Body:   requestData.Body,
-		UserID: requestData.UserID,
+		Body:          requestData.Body,
+		UserID:        requestData.UserID,
+		ParentID:      requestData.InReplyTo,
+		QuotedChirpID: requestData.QuoteChirpID,
 	}
 
-	chirp, err := cfg.dbQueries.CreateChirp(r.Context(), requestDataSend)
+	// The chirp and everything derived from it are saved together, so a bad
+	// media ID or a failed write doesn't leave a chirp behind
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
 	if err != nil {
-		log.Printf("Error finding chirp: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't start transaction", err)
 		return
 	}
+	defer tx.Rollback()
+
+	qtx := cfg.dbQueries.WithTx(tx)
 
-	data, err := json.Marshal(&chirp)
+	chirp, err := qtx.CreateChirp(r.Context(), requestDataSend)
 	if err != nil {
-		log.Printf("Error marshalling JSON: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't create chirp", err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
-	w.Write(data)
+	if len(requestData.MediaIDs) > 0 {
+
+		attachData := database.AttachMediaToChirpParams{
+			ChirpID:  chirp.ID,
+			MediaIds: requestData.MediaIDs,
+			UserID:   id,
+		}
+
+		// Media has to be your own and not already on another chirp
+		attached, err := qtx.AttachMediaToChirp(r.Context(), attachData)
+		if err != nil {
+			respondWithError(w, 500, "Couldn't attach media", err)
+			return
+		}
+
+		if attached != int64(len(requestData.MediaIDs)) {
+			respondWithError(w, 400, "Media not found or already attached", nil)
+			return
+		}
+	}
+
+	if err := flagChirp(r.Context(), qtx, chirp.ID, moderated); err != nil {
+		respondWithError(w, 500, "Couldn't flag chirp", err)
+		return
+	}
+
+	if err := saveMentions(r.Context(), qtx, chirp); err != nil {
+		respondWithError(w, 500, "Couldn't save mentions", err)
+		return
+	}
 
+	if err := saveHashtags(r.Context(), qtx, chirp); err != nil {
+		respondWithError(w, 500, "Couldn't save hashtags", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, 500, "Couldn't commit chirp", err)
+		return
+	}
+
+	sendBack := databaseChirpToChirp(chirp)
+
+	if err := cfg.addMedia(r.Context(), []*Chirp{&sendBack}); err != nil {
+		respondWithError(w, 500, "Couldn't get chirp media", err)
+		return
+	}
+
+	respondWithJSON(w, 200, sendBack)
 }
 
 func (cfg *apiConfig) handlerDeleteChirps(w http.ResponseWriter, r *http.Request) {
 
 	tokenString, err := auth.GetBearerToken(r.Header)
 	if err != nil {
-		log.Printf("Error obtaining token: %s", err)
-		w.WriteHeader(500)
+		respondWithAuthError(w, err)
 		return
 	}
 
-	id_JWT, err := auth.ValidateJWT(tokenString, cfg.tokenSecret)
+	id_JWT, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
 	if err != nil {
-		log.Printf("Error validating token: %s", err)
-		w.WriteHeader(401)
+		respondWithAuthError(w, err)
 		return
 	}
 
@@ -545,27 +986,29 @@ func (cfg *apiConfig) handlerDeleteChirps(w http.ResponseWriter, r *http.Request
 
 	chirpID, err := uuid.Parse(uuidString)
 	if err != nil {
-		log.Println("Invalid resource")
-		w.WriteHeader(404)
+		respondWithError(w, 404, "Chirp not found", err)
 		return
 	}
 
-	chirp, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID)
+	// Authors can still delete a chirp a moderator has hidden
+	sendData := database.GetChirpByIDForUserParams{
+		ID:     chirpID,
+		UserID: id_JWT,
+	}
+
+	chirp, err := cfg.dbQueries.GetChirpByIDForUser(r.Context(), sendData)
 	if err != nil {
-		log.Printf("Error finding chirp by ID: %s", err)
-		w.WriteHeader(404)
+		respondWithDBError(w, "Chirp not found", err)
 		return
 	}
 
 	if id_JWT != chirp.UserID {
-		log.Printf("Error unauthorized user: %s", err)
-		w.WriteHeader(401)
+		respondWithError(w, 403, "You can only delete your own chirps", nil)
 		return
 	}
 
-	if err := cfg.dbQueries.DeleteChirp(r.Context(), chirp.ID); err != nil {
-		log.Printf("Error deleting chirp by ID: %s", err)
-		w.WriteHeader(500)
+	if err := cfg.dbQueries.SoftDeleteChirp(r.Context(), chirp.ID); err != nil {
+		respondWithError(w, 500, "Couldn't delete chirp", err)
 		return
 	}
 
@@ -594,13 +1037,12 @@ func (cfg *apiConfig) handlerHits(w http.ResponseWriter, r *http.Request) {
 func (cfg *apiConfig) handlerReset(w http.ResponseWriter, r *http.Request) {
 
 	if cfg.platform != "dev" {
-		w.WriteHeader(403)
+		respondWithError(w, 403, "Reset is only allowed in dev", nil)
 		return
 	}
 
 	if err := cfg.dbQueries.DeleteUsers(r.Context()); err != nil {
-		log.Printf("Error Deleting users: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 500, "Couldn't delete users", err)
 		return
 	}
 
@@ -616,14 +1058,12 @@ func (cfg *apiConfig) handlerPolkaWebhooks(w http.ResponseWriter, r *http.Reques
 
 	apiKey, err := auth.GetAPIKey(r.Header)
 	if err != nil {
-		log.Printf("Error obtaining apiKey: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 401, "Missing API key", err)
 		return
 	}
 
 	if apiKey != cfg.polkaKey {
-		log.Printf("Error Unauthorized access: %s", err)
-		w.WriteHeader(401)
+		respondWithError(w, 401, "Invalid API key", nil)
 		return
 	}
 
@@ -636,8 +1076,7 @@ func (cfg *apiConfig) handlerPolkaWebhooks(w http.ResponseWriter, r *http.Reques
 
 	userReq := recieve{}
 	if err := json.NewDecoder(r.Body).Decode(&userReq); err != nil {
-		log.Printf("Error decoding parameters: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, 400, "Couldn't decode parameters", err)
 		return
 	}
 
@@ -647,8 +1086,7 @@ func (cfg *apiConfig) handlerPolkaWebhooks(w http.ResponseWriter, r *http.Reques
 	}
 
 	if err := cfg.dbQueries.UpgradeUser(r.Context(), userReq.Data.UserID); err != nil {
-		log.Printf("Error finding user: %s", err)
-		w.WriteHeader(404)
+		respondWithDBError(w, "User not found", err)
 		return
 	}
 
@@ -669,8 +1107,27 @@ func main() {
 	dbURL := os.Getenv("DB_URL")
 	platform := os.Getenv("PLATFORM")
 	tokenSecret := os.Getenv("TOKEN_STRING")
+	emailTokenSecret := os.Getenv("EMAIL_TOKEN_SECRET")
 	polkaKey := os.Getenv("POLKA_KEY")
 
+	chirpRetention := 30 * 24 * time.Hour
+	if retention := os.Getenv("CHIRP_RETENTION"); retention != "" {
+		parsed, err := time.ParseDuration(retention)
+		if err != nil {
+			log.Fatalf("Error parsing CHIRP_RETENTION: %s", err)
+		}
+		chirpRetention = parsed
+	}
+
+	trendingWindow := 24 * time.Hour
+	if window := os.Getenv("TRENDING_WINDOW"); window != "" {
+		parsed, err := time.ParseDuration(window)
+		if err != nil {
+			log.Fatalf("Error parsing TRENDING_WINDOW: %s", err)
+		}
+		trendingWindow = parsed
+	}
+
 	db, err := sql.Open("postgres", dbURL)
 	if err != nil {
 		log.Fatalf("Error connecting to the database: %s", err)
@@ -678,13 +1135,72 @@ func main() {
 
 	dbQueries := database.New(db)
 
+	jwtKeys, err := loadJWTKeys(tokenSecret)
+	if err != nil {
+		log.Fatalf("Error loading JWT keys: %s", err)
+	}
+
+	// Email verification and password reset tokens get their own secret, so
+	// leaking one doesn't let anyone forge the other kind of token
+	if emailTokenSecret == "" || emailTokenSecret == tokenSecret {
+		log.Fatalf("EMAIL_TOKEN_SECRET must be set and differ from TOKEN_STRING")
+	}
+
+	bannedWordsFile := os.Getenv("BANNED_WORDS_FILE")
+	bannedWords, err := loadBannedWords(context.Background(), dbQueries, bannedWordsFile)
+	if err != nil {
+		log.Fatalf("Error loading banned words: %s", err)
+	}
+
+	var appMailer mailer.Mailer
+	switch mailerType := os.Getenv("MAILER"); mailerType {
+	case "smtp":
+		appMailer = mailer.NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("MAIL_FROM"),
+		)
+	case "", "log":
+		mailOut := io.Writer(os.Stdout)
+		if mailLogFile := os.Getenv("MAIL_LOG_FILE"); mailLogFile != "" {
+			f, err := os.OpenFile(mailLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+			if err != nil {
+				log.Fatalf("Error opening MAIL_LOG_FILE: %s", err)
+			}
+			defer f.Close()
+			mailOut = f
+		}
+		appMailer = mailer.NewLogMailer(mailOut)
+	default:
+		log.Fatalf("Unknown MAILER: %s", mailerType)
+	}
+
 	apiCfg := apiConfig{
-		dbQueries:   dbQueries,
-		platform:    platform,
-		tokenSecret: tokenSecret,
-		polkaKey:    polkaKey,
+		db:               db,
+		dbQueries:        dbQueries,
+		platform:         platform,
+		emailTokenSecret: emailTokenSecret,
+		jwtKeys:          jwtKeys,
+		polkaKey:         polkaKey,
+
+		mailer: appMailer,
+
+		chirpFilter:        bannedWords,
+		bannedWords:        bannedWords,
+		persistBannedWords: bannedWordsFile == "",
+
+		trending: newTrendingCache(trendingWindow),
+
+		resetThrottle: newResetThrottle(passwordResetInterval),
+
+		storage: storage.NewLocalStorage("assets/media", "/app/assets/media"),
 	}
 
+	go apiCfg.purgeDeletedChirps(chirpRetention, time.Hour)
+	go apiCfg.refreshTrending(5 * time.Minute)
+
 	mux := http.NewServeMux()
 	mux.Handle("/app/", http.StripPrefix("/app/", apiCfg.middlewareMetricsInc(http.FileServer(http.Dir(".")))))
 	mux.Handle("/app/assets/logo", http.StripPrefix("/app/", http.FileServer(http.Dir("logo.png"))))
@@ -692,20 +1208,59 @@ func main() {
 	mux.HandleFunc("POST /api/login", apiCfg.handlerLogin)
 	mux.HandleFunc("POST /api/refresh", apiCfg.handlerRefresh)
 	mux.HandleFunc("POST /api/revoke", apiCfg.handlerRevoke)
+	mux.HandleFunc("GET /api/sessions", apiCfg.handlerGetSessions)
+	mux.HandleFunc("DELETE /api/sessions", apiCfg.handlerRevokeAllSessions)
+	mux.HandleFunc("DELETE /api/sessions/{sessionID}", apiCfg.handlerRevokeSession)
+	mux.HandleFunc("POST /api/password/forgot", apiCfg.handlerForgotPassword)
+	mux.HandleFunc("POST /api/password/reset", apiCfg.handlerResetPassword)
 
 	mux.HandleFunc("POST /api/users", apiCfg.handlerUsers)
 	mux.HandleFunc("PUT /api/users", apiCfg.handlerUsersUpdate)
+	mux.HandleFunc("POST /api/users/avatar", apiCfg.handlerUploadAvatar)
+	mux.HandleFunc("GET /api/users/{userID}", apiCfg.handlerGetUser)
+	mux.HandleFunc("POST /api/users/verify", apiCfg.handlerVerifyEmail)
+	mux.HandleFunc("POST /api/users/verify/resend", apiCfg.handlerResendVerification)
+	mux.HandleFunc("POST /api/users/{userID}/follow", apiCfg.handlerFollow)
+	mux.HandleFunc("DELETE /api/users/{userID}/follow", apiCfg.handlerUnfollow)
+
+	mux.HandleFunc("GET /api/feed", apiCfg.handlerFeed)
+	mux.HandleFunc("GET /api/mentions", apiCfg.handlerGetMentions)
 
 	mux.HandleFunc("GET /api/chirps", apiCfg.handlerGetChirps)
+	mux.HandleFunc("GET /api/chirps/search", apiCfg.handlerSearchChirps)
+	mux.HandleFunc("GET /api/trending", apiCfg.handlerGetTrending)
 	mux.HandleFunc("GET /api/chirps/{chirpID}", apiCfg.handlerGetChirpID)
+	mux.HandleFunc("GET /api/chirps/{chirpID}/thread", apiCfg.handlerGetChirpThread)
 	mux.HandleFunc("POST /api/chirps", apiCfg.handlerChirps)
+	mux.HandleFunc("POST /api/media", apiCfg.handlerUploadMedia)
+	mux.HandleFunc("PUT /api/chirps/{chirpID}", apiCfg.handlerUpdateChirp)
+	mux.HandleFunc("GET /api/chirps/{chirpID}/revisions", apiCfg.handlerGetChirpRevisions)
 	mux.HandleFunc("DELETE /api/chirps/{chirpID}", apiCfg.handlerDeleteChirps)
-
-	mux.HandleFunc("GET /admin/metrics", apiCfg.handlerHits)
-	mux.HandleFunc("POST /admin/reset", apiCfg.handlerReset)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/restore", apiCfg.handlerRestoreChirp)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/like", apiCfg.handlerLikeChirp)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/like", apiCfg.handlerUnlikeChirp)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/rechirp", apiCfg.handlerRechirp)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/rechirp", apiCfg.handlerUndoRechirp)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/report", apiCfg.handlerReportChirp)
+
+	mux.Handle("GET /admin/metrics", apiCfg.middlewareRequireRole(auth.RoleAdmin, apiCfg.handlerHits))
+	mux.Handle("POST /admin/reset", apiCfg.middlewareRequireRole(auth.RoleAdmin, apiCfg.handlerReset))
+	mux.Handle("GET /admin/banned-words", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerGetBannedWords))
+	mux.Handle("PUT /admin/banned-words/{word}", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerPutBannedWord))
+	mux.Handle("DELETE /admin/banned-words/{word}", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerDeleteBannedWord))
+	mux.Handle("GET /admin/flags", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerGetChirpFlags))
+	mux.Handle("GET /admin/reports", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerGetReports))
+	mux.Handle("POST /admin/reports/{reportID}/resolve", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerResolveReport))
+	mux.Handle("POST /admin/reports/{reportID}/dismiss", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerDismissReport))
+	mux.Handle("POST /admin/chirps/{chirpID}/hide", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerHideChirp))
+	mux.Handle("DELETE /admin/chirps/{chirpID}/hide", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerUnhideChirp))
+	mux.Handle("POST /admin/users/{userID}/suspend", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerSuspendUser))
+	mux.Handle("DELETE /admin/users/{userID}/suspend", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerUnsuspendUser))
+	mux.Handle("PUT /admin/users/{userID}/role", apiCfg.middlewareRequireRole(auth.RoleAdmin, apiCfg.handlerUpdateUserRole))
 
 	mux.HandleFunc("POST /api/polka/webhooks", apiCfg.handlerPolkaWebhooks)
 	mux.HandleFunc("GET /api/healthz", readiness)
+	mux.HandleFunc("GET /.well-known/jwks.json", apiCfg.handlerJWKS)
 
 	server := &http.Server{
 		Addr:    ":8080",
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+
+	// Register the remaining decoder image.Decode needs
+	_ "image/gif"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/thumbnail"
+	"github.com/google/uuid"
+)
+
+const (
+	maxMediaSize  = 5 << 20
+	maxChirpMedia = 4
+
+	// Decoding allocates width*height pixels up front, so a tiny file that
+	// claims to be enormous is refused before it is decoded
+	maxMediaDimension = 8192
+
+	thumbnailSize = 320
+)
+
+// Only formats the standard library can decode are accepted, so every
+// upload gets its dimensions and a thumbnail.
+var mediaExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+type Media struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+}
+
+func (cfg *apiConfig) databaseMediaToMedia(media database.MediaAttachment) Media {
+
+	// Small images are their own thumbnail
+	thumbnailURL := cfg.storage.URL(media.StorageKey)
+	if media.ThumbnailKey.Valid {
+		thumbnailURL = cfg.storage.URL(media.ThumbnailKey.String)
+	}
+
+	return Media{
+		ID:           media.ID,
+		URL:          cfg.storage.URL(media.StorageKey),
+		ThumbnailURL: thumbnailURL,
+		ContentType:  media.ContentType,
+		Width:        media.Width,
+		Height:       media.Height,
+	}
+}
+
+// addMedia fills in the attachments of a batch of chirps with a single query.
+func (cfg *apiConfig) addMedia(ctx context.Context, chirps []*Chirp) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		ids = append(ids, chirp.ID)
+	}
+
+	rows, err := cfg.dbQueries.GetChirpsMedia(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byChirp := make(map[uuid.UUID][]Media, len(rows))
+	for _, row := range rows {
+		byChirp[row.ChirpID.UUID] = append(byChirp[row.ChirpID.UUID], cfg.databaseMediaToMedia(row))
+	}
+
+	for _, chirp := range chirps {
+		chirp.Media = byChirp[chirp.ID]
+	}
+
+	return nil
+}
+
+func (cfg *apiConfig) handlerUploadMedia(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	blocked, err := cfg.checkCanPost(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't find user", err)
+		return
+	}
+
+	if blocked != "" {
+		respondWithError(w, 403, blocked, nil)
+		return
+	}
+
+	// Leave some room for the multipart headers around the file
+	r.Body = http.MaxBytesReader(w, r.Body, maxMediaSize+64<<10)
+	if err := r.ParseMultipartForm(maxMediaSize); err != nil {
+		respondWithError(w, 400, "Media must be a multipart upload of at most 5 MB", err)
+		return
+	}
+
+	file, _, err := r.FormFile("media")
+	if err != nil {
+		respondWithError(w, 400, "Missing media file", err)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxMediaSize+1))
+	if err != nil {
+		respondWithError(w, 400, "Couldn't read media file", err)
+		return
+	}
+
+	if len(data) > maxMediaSize {
+		respondWithError(w, 400, "Media must be at most 5 MB", nil)
+		return
+	}
+
+	// Trust the file contents, not the client's Content-Type
+	contentType := http.DetectContentType(data)
+	ext, ok := mediaExtensions[contentType]
+	if !ok {
+		respondWithError(w, 400, "Media must be a PNG, JPEG or GIF image", nil)
+		return
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		respondWithError(w, 400, "Couldn't read image", err)
+		return
+	}
+
+	if config.Width > maxMediaDimension || config.Height > maxMediaDimension {
+		respondWithError(w, 400, "Image must be at most 8192 pixels wide and high", nil)
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		respondWithError(w, 400, "Couldn't read image", err)
+		return
+	}
+
+	mediaID := uuid.New()
+	storageKey := mediaID.String() + ext
+
+	if err := cfg.storage.Put(r.Context(), storageKey, bytes.NewReader(data)); err != nil {
+		respondWithError(w, 500, "Couldn't save media", err)
+		return
+	}
+
+	thumbnailKey := sql.NullString{}
+	if config.Width > thumbnailSize || config.Height > thumbnailSize {
+
+		thumb := thumbnail.Resize(img, thumbnailSize)
+
+		// JPEG has no transparency, so only photos are thumbnailed as JPEG
+		var buf bytes.Buffer
+		thumbExt := ".png"
+		if contentType == "image/jpeg" {
+			thumbExt = ".jpg"
+			err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80})
+		} else {
+			err = png.Encode(&buf, thumb)
+		}
+		if err != nil {
+			cfg.deleteStoredMedia(r.Context(), storageKey)
+			respondWithError(w, 500, "Couldn't create thumbnail", err)
+			return
+		}
+
+		thumbnailKey = sql.NullString{String: mediaID.String() + "_thumb" + thumbExt, Valid: true}
+
+		if err := cfg.storage.Put(r.Context(), thumbnailKey.String, &buf); err != nil {
+			cfg.deleteStoredMedia(r.Context(), storageKey)
+			respondWithError(w, 500, "Couldn't save thumbnail", err)
+			return
+		}
+	}
+
+	sendData := database.CreateMediaAttachmentParams{
+		ID:           mediaID,
+		UserID:       userID,
+		ContentType:  contentType,
+		SizeBytes:    int64(len(data)),
+		Width:        int32(config.Width),
+		Height:       int32(config.Height),
+		StorageKey:   storageKey,
+		ThumbnailKey: thumbnailKey,
+	}
+
+	media, err := cfg.dbQueries.CreateMediaAttachment(r.Context(), sendData)
+	if err != nil {
+		cfg.deleteStoredMedia(r.Context(), storageKey, thumbnailKey.String)
+		respondWithError(w, 500, "Couldn't save media", err)
+		return
+	}
+
+	respondWithJSON(w, 201, cfg.databaseMediaToMedia(media))
+}
+
+// deleteStoredMedia cleans up files after a failed upload. Errors are only
+// logged since the upload has already failed.
+func (cfg *apiConfig) deleteStoredMedia(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := cfg.storage.Delete(ctx, key); err != nil {
+			log.Printf("Error deleting media %s: %s", key, err)
+		}
+	}
+}
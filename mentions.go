@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/mentions"
+	"github.com/IsahiRea/chirp/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// saveMentions records who a new chirp mentions. Handles that don't belong
+// to anyone, and authors mentioning themselves, are skipped by the query.
+func saveMentions(ctx context.Context, q *database.Queries, chirp database.Chirp) error {
+
+	handles := mentions.Parse(chirp.Body)
+	if len(handles) == 0 {
+		return nil
+	}
+
+	sendData := database.CreateChirpMentionsParams{
+		ChirpID:  chirp.ID,
+		Handles:  handles,
+		AuthorID: chirp.UserID,
+	}
+
+	return q.CreateChirpMentions(ctx, sendData)
+}
+
+// replaceMentions brings an edited chirp's mentions in line with its new
+// body. Users it still mentions keep their rows.
+func replaceMentions(ctx context.Context, q *database.Queries, chirp database.Chirp) error {
+
+	sendData := database.DeleteChirpMentionsParams{
+		ChirpID:     chirp.ID,
+		KeepHandles: mentions.Parse(chirp.Body),
+	}
+
+	if err := q.DeleteChirpMentions(ctx, sendData); err != nil {
+		return err
+	}
+
+	return saveMentions(ctx, q, chirp)
+}
+
+func (cfg *apiConfig) handlerGetMentions(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	id, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid limit", err)
+		return
+	}
+
+	cursorCreatedAt, cursorID, err := parseCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid cursor", err)
+		return
+	}
+
+	sendData := database.GetMentionsPageParams{
+		UserID:          id,
+		CursorCreatedAt: cursorCreatedAt,
+		CursorID:        cursorID,
+		PageLimit:       int32(limit + 1),
+	}
+
+	dbChirps, err := cfg.dbQueries.GetMentionsPage(r.Context(), sendData)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't get mentions", err)
+		return
+	}
+
+	sendBack := newChirpsPage(databaseChirpsToChirps(dbChirps), limit)
+
+	if err := cfg.addLikeStats(r.Context(), chirpRefs(sendBack.Chirps), uuid.NullUUID{UUID: id, Valid: true}); err != nil {
+		respondWithError(w, 500, "Couldn't get like counts", err)
+		return
+	}
+
+	if err := cfg.addMedia(r.Context(), chirpRefs(sendBack.Chirps)); err != nil {
+		respondWithError(w, 500, "Couldn't get chirp media", err)
+		return
+	}
+
+	if embedsAuthor(r) {
+		if err := cfg.addAuthors(r.Context(), chirpRefs(sendBack.Chirps)); err != nil {
+			respondWithError(w, 500, "Couldn't get chirp authors", err)
+			return
+		}
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
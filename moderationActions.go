@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/google/uuid"
+)
+
+// checkCanPost returns why the user may not post, or an empty string when
+// they can. Suspended users can still read, and so can users who have not
+// verified their email yet.
+func (cfg *apiConfig) checkCanPost(ctx context.Context, userID uuid.UUID) (string, error) {
+	user, err := cfg.dbQueries.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if user.SuspendedAt.Valid {
+		return "Account is suspended", nil
+	}
+
+	if !user.EmailVerifiedAt.Valid {
+		return "Email address is not verified", nil
+	}
+
+	return "", nil
+}
+
+// checkCanModerate returns why the caller may not act on the target user's
+// account, or an empty string when they can. The caller has to outrank the
+// target, so nobody can suspend their peers, their superiors or themselves.
+func (cfg *apiConfig) checkCanModerate(r *http.Request, targetID uuid.UUID) (string, error) {
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := auth.ValidateJWTClaims(tokenString, cfg.jwtKeys)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := cfg.dbQueries.GetUserByID(r.Context(), targetID)
+	if err != nil {
+		return "", err
+	}
+
+	if !auth.Outranks(claims.Role, target.Role) {
+		return "You can only moderate users below your role", nil
+	}
+
+	return "", nil
+}
+
+func (cfg *apiConfig) handlerHideChirp(w http.ResponseWriter, r *http.Request) {
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	rows, err := cfg.dbQueries.HideChirp(r.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't hide chirp", err)
+		return
+	}
+
+	if rows == 0 {
+		respondWithError(w, 404, "Chirp not found", nil)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUnhideChirp(w http.ResponseWriter, r *http.Request) {
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	rows, err := cfg.dbQueries.UnhideChirp(r.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't unhide chirp", err)
+		return
+	}
+
+	if rows == 0 {
+		respondWithError(w, 404, "Chirp not found", nil)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerSuspendUser(w http.ResponseWriter, r *http.Request) {
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, 404, "User not found", err)
+		return
+	}
+
+	blocked, err := cfg.checkCanModerate(r, userID)
+	if err != nil {
+		respondWithDBError(w, "User not found", err)
+		return
+	}
+
+	if blocked != "" {
+		respondWithError(w, 403, blocked, nil)
+		return
+	}
+
+	rows, err := cfg.dbQueries.SuspendUser(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't suspend user", err)
+		return
+	}
+
+	if rows == 0 {
+		respondWithError(w, 404, "User not found", nil)
+		return
+	}
+
+	// Stop existing sessions from minting new access tokens
+	if err := cfg.dbQueries.RevokeUserRefreshTokens(r.Context(), userID); err != nil {
+		respondWithError(w, 500, "Couldn't revoke refresh tokens", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUnsuspendUser(w http.ResponseWriter, r *http.Request) {
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, 404, "User not found", err)
+		return
+	}
+
+	blocked, err := cfg.checkCanModerate(r, userID)
+	if err != nil {
+		respondWithDBError(w, "User not found", err)
+		return
+	}
+
+	if blocked != "" {
+		respondWithError(w, 403, blocked, nil)
+		return
+	}
+
+	rows, err := cfg.dbQueries.UnsuspendUser(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't unsuspend user", err)
+		return
+	}
+
+	if rows == 0 {
+		respondWithError(w, 404, "User not found", nil)
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/mailer"
+	"github.com/IsahiRea/chirp/internal/validation"
+)
+
+const passwordResetTTL = time.Hour
+
+// passwordResetInterval is how long an address has to wait between reset
+// emails, so the endpoint can't be used to flood someone's inbox.
+const passwordResetInterval = 5 * time.Minute
+
+// resetThrottle remembers when each address last asked for a reset.
+type resetThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	lastSent map[string]time.Time
+}
+
+func newResetThrottle(interval time.Duration) *resetThrottle {
+	return &resetThrottle{
+		interval: interval,
+		lastSent: map[string]time.Time{},
+	}
+}
+
+// allow reports whether email may be sent another reset and, if so, starts
+// its wait. Addresses are compared ignoring case.
+func (t *resetThrottle) allow(email string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, sent := range t.lastSent {
+		if now.Sub(sent) >= t.interval {
+			delete(t.lastSent, key)
+		}
+	}
+
+	key := strings.ToLower(strings.TrimSpace(email))
+	if _, ok := t.lastSent[key]; ok {
+		return false
+	}
+
+	t.lastSent[key] = now
+	return true
+}
+
+func (cfg *apiConfig) handlerForgotPassword(w http.ResponseWriter, r *http.Request) {
+
+	type recieve struct {
+		Email string `json:"email"`
+	}
+
+	requestData := recieve{}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		respondWithError(w, 400, "Couldn't decode parameters", err)
+		return
+	}
+
+	if errs := validation.Validate(
+		validation.Field("email", requestData.Email, validation.Required, validation.Email),
+	); errs != nil {
+		respondWithErrorDetails(w, 400, "Invalid request", nil, errs)
+		return
+	}
+
+	// Answer straight away, the same way whether or not the account exists
+	// or the address is being throttled, so neither the response nor how long
+	// it takes can be used to find out who is registered
+	if cfg.resetThrottle.allow(requestData.Email, time.Now()) {
+		go cfg.sendPasswordReset(requestData.Email)
+	}
+
+	w.WriteHeader(204)
+}
+
+// sendPasswordReset mails a reset token to email if it belongs to an account.
+// It runs after the response has gone out, so failures are only logged.
+func (cfg *apiConfig) sendPasswordReset(email string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	user, err := cfg.dbQueries.GetHashPassByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	if err != nil {
+		log.Printf("Error finding user for password reset: %s", err)
+		return
+	}
+
+	token, err := auth.MakeSignedToken(cfg.emailTokenSecret)
+	if err != nil {
+		log.Printf("Error creating reset token for user %s: %s", user.ID, err)
+		return
+	}
+
+	sendData := database.CreatePasswordResetParams{
+		TokenHash: auth.HashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+
+	if err := cfg.dbQueries.CreatePasswordReset(ctx, sendData); err != nil {
+		log.Printf("Error saving reset token for user %s: %s", user.ID, err)
+		return
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Chirpy password",
+		Body: fmt.Sprintf("Someone asked to reset the password for this Chirpy account.\n\nUse this token to choose a new password:\n\n%s\n\nIt expires in %s. If this wasn't you, you can ignore this email.\n",
+			token, passwordResetTTL),
+	}
+
+	if err := cfg.mailer.Send(ctx, msg); err != nil {
+		log.Printf("Error sending password reset email to user %s: %s", user.ID, err)
+	}
+}
+
+func (cfg *apiConfig) handlerResetPassword(w http.ResponseWriter, r *http.Request) {
+
+	type recieve struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	requestData := recieve{}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		respondWithError(w, 400, "Couldn't decode parameters", err)
+		return
+	}
+
+	if errs := validation.Validate(
+		validation.Field("token", requestData.Token, validation.Required),
+		validation.Field("password", requestData.Password, validation.Required, validation.Password(validation.DefaultPasswordPolicy)),
+	); errs != nil {
+		respondWithErrorDetails(w, 400, "Invalid request", nil, errs)
+		return
+	}
+
+	if err := auth.VerifySignedToken(requestData.Token, cfg.emailTokenSecret); err != nil {
+		respondWithError(w, 400, "Invalid or expired token", err)
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(requestData.Password)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't hash password", err)
+		return
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't start transaction", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	reset, err := qtx.UsePasswordReset(r.Context(), auth.HashToken(requestData.Token))
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, 400, "Invalid or expired token", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, 500, "Couldn't use reset token", err)
+		return
+	}
+
+	sendData := database.UpdatePasswordParams{
+		ID:             reset.UserID,
+		HashedPassword: hashedPassword,
+	}
+
+	if _, err := qtx.UpdatePassword(r.Context(), sendData); err != nil {
+		respondWithError(w, 500, "Couldn't update password", err)
+		return
+	}
+
+	// Any other links that were mailed out stop working too
+	if err := qtx.ExpireUserPasswordResets(r.Context(), reset.UserID); err != nil {
+		respondWithError(w, 500, "Couldn't expire reset tokens", err)
+		return
+	}
+
+	// Whoever had the old password may still be logged in somewhere
+	if err := qtx.RevokeUserRefreshTokens(r.Context(), reset.UserID); err != nil {
+		respondWithError(w, 500, "Couldn't revoke refresh tokens", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, 500, "Couldn't commit password reset", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
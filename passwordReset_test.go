@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResetThrottle(t *testing.T) {
+	throttle := newResetThrottle(time.Minute)
+	now := time.Now()
+
+	if !throttle.allow("alice@example.com", now) {
+		t.Fatalf("first request was throttled")
+	}
+
+	if throttle.allow("Alice@Example.com ", now.Add(30*time.Second)) {
+		t.Errorf("second request inside the interval was allowed")
+	}
+
+	if !throttle.allow("bob@example.com", now.Add(30*time.Second)) {
+		t.Errorf("another address was throttled")
+	}
+
+	if !throttle.allow("alice@example.com", now.Add(time.Minute)) {
+		t.Errorf("request after the interval was throttled")
+	}
+
+	if len(throttle.lastSent) != 2 {
+		t.Errorf("len(lastSent) = %d, want 2 after expired entries are dropped", len(throttle.lastSent))
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	maxBioLength  = 160
+	maxAvatarSize = 1 << 20
+
+	// Avatars live under the /app/ file server, which serves the working
+	// directory
+	avatarDir       = "assets/avatars"
+	avatarURLPrefix = "/app/" + avatarDir + "/"
+)
+
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Author is the minimal public view of a user embedded in chirps.
+type Author struct {
+	ID          uuid.UUID `json:"id"`
+	Handle      string    `json:"handle"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url"`
+}
+
+// embedsAuthor reports whether the client asked for ?embed=author. embed
+// takes a comma separated list so more objects can be added later.
+func embedsAuthor(r *http.Request) bool {
+	return slices.Contains(strings.Split(r.URL.Query().Get("embed"), ","), "author")
+}
+
+// addAuthors fills in the author of a batch of chirps with a single query.
+func (cfg *apiConfig) addAuthors(ctx context.Context, chirps []*Chirp) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		ids = append(ids, chirp.UserID)
+	}
+
+	profiles, err := cfg.dbQueries.GetUserProfiles(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byUser := make(map[uuid.UUID]*Author, len(profiles))
+	for _, profile := range profiles {
+		byUser[profile.ID] = &Author{
+			ID:          profile.ID,
+			Handle:      profile.Handle.String,
+			DisplayName: profile.DisplayName,
+			AvatarURL:   profile.AvatarUrl,
+		}
+	}
+
+	for _, chirp := range chirps {
+		chirp.Author = byUser[chirp.UserID]
+	}
+
+	return nil
+}
+
+func (cfg *apiConfig) handlerGetUser(w http.ResponseWriter, r *http.Request) {
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, 404, "User not found", err)
+		return
+	}
+
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithDBError(w, "User not found", err)
+		return
+	}
+
+	// Public, so the email address is deliberately left out
+	sendBack := struct {
+		ID          uuid.UUID `json:"id"`
+		CreatedAt   time.Time `json:"created_at"`
+		Handle      string    `json:"handle"`
+		DisplayName string    `json:"display_name"`
+		Bio         string    `json:"bio"`
+		AvatarURL   string    `json:"avatar_url"`
+		Premium     bool      `json:"is_chirp_red"`
+	}{
+		user.ID,
+		user.CreatedAt,
+		user.Handle.String,
+		user.DisplayName,
+		user.Bio,
+		user.AvatarUrl,
+		user.IsChirpyRed,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
+
+func (cfg *apiConfig) handlerUploadAvatar(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	// Leave some room for the multipart headers around the file
+	r.Body = http.MaxBytesReader(w, r.Body, maxAvatarSize+64<<10)
+	if err := r.ParseMultipartForm(maxAvatarSize); err != nil {
+		respondWithError(w, 400, "Avatar must be a multipart upload of at most 1 MB", err)
+		return
+	}
+
+	file, _, err := r.FormFile("avatar")
+	if err != nil {
+		respondWithError(w, 400, "Missing avatar file", err)
+		return
+	}
+	defer file.Close()
+
+	// Trust the file contents, not the client's Content-Type
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		respondWithError(w, 400, "Couldn't read avatar file", err)
+		return
+	}
+	head = head[:n]
+
+	ext, ok := avatarExtensions[http.DetectContentType(head)]
+	if !ok {
+		respondWithError(w, 400, "Avatar must be a PNG, JPEG, GIF or WebP image", nil)
+		return
+	}
+
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithDBError(w, "User not found", err)
+		return
+	}
+
+	// A new name on every upload means browsers never show a cached old avatar
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		respondWithError(w, 500, "Couldn't name avatar file", err)
+		return
+	}
+	fileName := hex.EncodeToString(randomBytes) + ext
+
+	if err := os.MkdirAll(avatarDir, 0o755); err != nil {
+		respondWithError(w, 500, "Couldn't create avatar directory", err)
+		return
+	}
+
+	dst, err := os.Create(filepath.Join(avatarDir, fileName))
+	if err != nil {
+		respondWithError(w, 500, "Couldn't save avatar", err)
+		return
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, io.MultiReader(bytes.NewReader(head), file)); err != nil {
+		respondWithError(w, 500, "Couldn't save avatar", err)
+		return
+	}
+
+	sendData := database.UpdateUserAvatarParams{
+		ID:        userID,
+		AvatarUrl: avatarURLPrefix + fileName,
+	}
+
+	if err := cfg.dbQueries.UpdateUserAvatar(r.Context(), sendData); err != nil {
+		respondWithError(w, 500, "Couldn't update avatar", err)
+		return
+	}
+
+	if strings.HasPrefix(user.AvatarUrl, avatarURLPrefix) {
+		oldPath := filepath.Join(avatarDir, filepath.Base(user.AvatarUrl))
+		if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
+			log.Printf("Error removing old avatar %s: %s", oldPath, err)
+		}
+	}
+
+	sendBack := struct {
+		AvatarURL string `json:"avatar_url"`
+	}{
+		sendData.AvatarUrl,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerRechirp(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	blocked, err := cfg.checkCanPost(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't find user", err)
+		return
+	}
+
+	if blocked != "" {
+		respondWithError(w, 403, blocked, nil)
+		return
+	}
+
+	chirp, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID)
+	if err != nil {
+		respondWithDBError(w, "Chirp not found", err)
+		return
+	}
+
+	// Own chirps already show up in the author's listing
+	if chirp.UserID == userID {
+		respondWithError(w, 400, "You can't rechirp your own chirp", nil)
+		return
+	}
+
+	sendData := database.CreateRechirpParams{
+		UserID:  userID,
+		ChirpID: chirp.ID,
+	}
+
+	if err := cfg.dbQueries.CreateRechirp(r.Context(), sendData); err != nil {
+		respondWithError(w, 500, "Couldn't rechirp", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerUndoRechirp(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	sendData := database.DeleteRechirpParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	}
+
+	if err := cfg.dbQueries.DeleteRechirp(r.Context(), sendData); err != nil {
+		respondWithError(w, 500, "Couldn't undo rechirp", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
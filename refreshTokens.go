@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+)
+
+const refreshTokenTTL = 60 * 24 * time.Hour
+
+// createRefreshToken issues a refresh token in familyID. Logging in starts a
+// new family; every refresh adds the next token to the same family with the
+// hash of the token it replaced as its parent. Only the hash is stored, so
+// the returned token is the one chance to hand it to the client. q may be
+// bound to a transaction.
+func createRefreshToken(ctx context.Context, q *database.Queries, userID, familyID uuid.UUID, parentHash sql.NullString, device deviceInfo) (string, error) {
+
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	sendData := database.CreateRefeshTokenParams{
+		TokenHash:       auth.HashToken(refreshToken),
+		UserID:          userID,
+		ExpiresAt:       time.Now().Add(refreshTokenTTL),
+		FamilyID:        familyID,
+		ParentTokenHash: parentHash,
+		UserAgent:       device.UserAgent,
+		IpAddress:       device.IPAddress,
+	}
+
+	if err := q.CreateRefeshToken(ctx, sendData); err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// revokeTokenFamily ends a session after a refresh token was reused. The
+// request is rejected either way, so a failure is only logged.
+func (cfg *apiConfig) revokeTokenFamily(ctx context.Context, familyID uuid.UUID) {
+
+	revoked, err := cfg.dbQueries.RevokeRefreshTokenFamily(ctx, familyID)
+	if err != nil {
+		log.Printf("Error revoking refresh token family %s: %s", familyID, err)
+		return
+	}
+
+	log.Printf("Refresh token reuse in family %s, revoked %d tokens", familyID, revoked)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/IsahiRea/chirp/internal/pagination"
+	"github.com/IsahiRea/chirp/internal/validation"
+	"github.com/google/uuid"
+)
+
+// reportReasons must match the CHECK constraint on reports.reason
+var reportReasons = map[string]bool{
+	"spam":           true,
+	"abuse":          true,
+	"harassment":     true,
+	"hate":           true,
+	"misinformation": true,
+	"other":          true,
+}
+
+const maxReportDetails = 500
+
+type Report struct {
+	ID         uuid.UUID     `json:"id"`
+	ChirpID    uuid.UUID     `json:"chirp_id"`
+	ReporterID uuid.UUID     `json:"reporter_id"`
+	Reason     string        `json:"reason"`
+	Details    string        `json:"details"`
+	Status     string        `json:"status"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	ResolvedAt *time.Time    `json:"resolved_at"`
+	ResolvedBy uuid.NullUUID `json:"resolved_by"`
+}
+
+func databaseReportToReport(report database.Report) Report {
+	var resolvedAt *time.Time
+	if report.ResolvedAt.Valid {
+		resolvedAt = &report.ResolvedAt.Time
+	}
+
+	return Report{
+		ID:         report.ID,
+		ChirpID:    report.ChirpID,
+		ReporterID: report.ReporterID,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Status:     report.Status,
+		CreatedAt:  report.CreatedAt,
+		UpdatedAt:  report.UpdatedAt,
+		ResolvedAt: resolvedAt,
+		ResolvedBy: report.ResolvedBy,
+	}
+}
+
+// validateReportDetails checks the optional free text of a report. The limit
+// is in characters, not bytes.
+func validateReportDetails(details string) validation.Errors {
+	return validation.Validate(
+		validation.Field("details", details, validation.MaxRunes(maxReportDetails)),
+	)
+}
+
+func (cfg *apiConfig) handlerReportChirp(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 404, "Chirp not found", err)
+		return
+	}
+
+	type recieve struct {
+		Reason  string `json:"reason"`
+		Details string `json:"details"`
+	}
+
+	requestData := recieve{}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		respondWithError(w, 400, "Couldn't decode parameters", err)
+		return
+	}
+
+	if !reportReasons[requestData.Reason] {
+		respondWithError(w, 400, "Invalid report reason", nil)
+		return
+	}
+
+	if errs := validateReportDetails(requestData.Details); errs != nil {
+		respondWithErrorDetails(w, 400, "Invalid report", nil, errs)
+		return
+	}
+
+	if _, err := cfg.dbQueries.GetChirpByID(r.Context(), chirpID); err != nil {
+		respondWithDBError(w, "Chirp not found", err)
+		return
+	}
+
+	sendData := database.CreateReportParams{
+		ChirpID:    chirpID,
+		ReporterID: userID,
+		Reason:     requestData.Reason,
+		Details:    requestData.Details,
+	}
+
+	report, err := cfg.dbQueries.CreateReport(r.Context(), sendData)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The reporter already has an open report on this chirp
+		respondWithError(w, 409, "You already reported this chirp", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, 500, "Couldn't create report", err)
+		return
+	}
+
+	respondWithJSON(w, 201, databaseReportToReport(report))
+}
+
+func (cfg *apiConfig) handlerGetReports(w http.ResponseWriter, r *http.Request) {
+
+	// Moderators mostly want the queue, so only open reports by default
+	status := sql.NullString{String: "open", Valid: true}
+	switch s := r.URL.Query().Get("status"); s {
+	case "":
+	case "all":
+		status = sql.NullString{}
+	case "open", "resolved", "dismissed":
+		status.String = s
+	default:
+		respondWithError(w, 400, "Invalid status", nil)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid limit", err)
+		return
+	}
+
+	offset, err := pagination.ParseOffset(r.URL.Query().Get("offset"))
+	if err != nil {
+		respondWithError(w, 400, "Invalid offset", err)
+		return
+	}
+
+	sendData := database.GetReportsParams{
+		Status:     status,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	}
+
+	dbReports, err := cfg.dbQueries.GetReports(r.Context(), sendData)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't get reports", err)
+		return
+	}
+
+	reports := make([]Report, 0, len(dbReports))
+	for _, report := range dbReports {
+		reports = append(reports, databaseReportToReport(report))
+	}
+
+	sendBack := struct {
+		Reports []Report `json:"reports"`
+	}{
+		reports,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
+
+func (cfg *apiConfig) handlerResolveReport(w http.ResponseWriter, r *http.Request) {
+	cfg.closeReport(w, r, "resolved")
+}
+
+func (cfg *apiConfig) handlerDismissReport(w http.ResponseWriter, r *http.Request) {
+	cfg.closeReport(w, r, "dismissed")
+}
+
+// closeReport takes an open report out of the queue with the given status
+func (cfg *apiConfig) closeReport(w http.ResponseWriter, r *http.Request, status string) {
+
+	reportID, err := uuid.Parse(r.PathValue("reportID"))
+	if err != nil {
+		respondWithError(w, 404, "Report not found", err)
+		return
+	}
+
+	sendData := database.CloseReportParams{
+		Status:     status,
+		ResolvedBy: cfg.viewerID(r),
+		ID:         reportID,
+	}
+
+	report, err := cfg.dbQueries.CloseReport(r.Context(), sendData)
+	if err != nil {
+		respondWithDBError(w, "Open report not found", err)
+		return
+	}
+
+	respondWithJSON(w, 200, databaseReportToReport(report))
+}
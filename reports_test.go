@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateReportDetails(t *testing.T) {
+	tests := []struct {
+		name    string
+		details string
+		wantErr bool
+	}{
+		{name: "Empty", details: "", wantErr: false},
+		{name: "At the limit", details: strings.Repeat("a", maxReportDetails), wantErr: false},
+		{name: "Over the limit", details: strings.Repeat("a", maxReportDetails+1), wantErr: true},
+		{name: "Multibyte at the limit", details: strings.Repeat("é", maxReportDetails), wantErr: false},
+		{name: "Emoji at the limit", details: strings.Repeat("🐦", maxReportDetails), wantErr: false},
+		{name: "Multibyte over the limit", details: strings.Repeat("日", maxReportDetails+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateReportDetails(tt.details)
+			if (errs != nil) != tt.wantErr {
+				t.Errorf("validateReportDetails() = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+)
+
+// middlewareRequireRole only lets the request through when the caller's
+// access token carries at least the given role.
+func (cfg *apiConfig) middlewareRequireRole(role string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		tokenString, err := auth.GetBearerToken(r.Header)
+		if err != nil {
+			respondWithAuthError(w, err)
+			return
+		}
+
+		claims, err := auth.ValidateJWTClaims(tokenString, cfg.jwtKeys)
+		if err != nil {
+			respondWithAuthError(w, err)
+			return
+		}
+
+		if !auth.HasRole(claims.Role, role) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm=%q, error="insufficient_scope"`, authRealm))
+			respondWithError(w, 403, "Requires role "+role, fmt.Errorf("user %s has role %s", claims.Subject, claims.Role))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (cfg *apiConfig) handlerUpdateUserRole(w http.ResponseWriter, r *http.Request) {
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, 404, "User not found", err)
+		return
+	}
+
+	type recieve struct {
+		Role string `json:"role"`
+	}
+
+	requestData := recieve{}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		respondWithError(w, 400, "Couldn't decode parameters", err)
+		return
+	}
+
+	if !auth.ValidRole(requestData.Role) {
+		respondWithError(w, 400, "Role must be user, moderator or admin", nil)
+		return
+	}
+
+	// Stops the last admin from locking everyone out by demoting themselves
+	if viewer := cfg.viewerID(r); viewer.Valid && viewer.UUID == userID {
+		respondWithError(w, 400, "You can't change your own role", nil)
+		return
+	}
+
+	sendData := database.UpdateUserRoleParams{
+		ID:   userID,
+		Role: requestData.Role,
+	}
+
+	user, err := cfg.dbQueries.UpdateUserRole(r.Context(), sendData)
+	if err != nil {
+		respondWithDBError(w, "User not found", err)
+		return
+	}
+
+	sendBack := struct {
+		ID        uuid.UUID `json:"id"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Email     string    `json:"email"`
+		Role      string    `json:"role"`
+	}{
+		user.ID,
+		user.UpdatedAt,
+		user.Email,
+		user.Role,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/IsahiRea/chirp/internal/auth"
+	"github.com/IsahiRea/chirp/internal/database"
+	"github.com/google/uuid"
+)
+
+// A session is one refresh token family: everything issued from a single
+// login. Its ID is the family ID.
+
+const maxUserAgentLength = 512
+
+type deviceInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// requestDevice describes the client behind r for the session list. The IP
+// is the direct peer; X-Forwarded-For is ignored because anyone can set it.
+func requestDevice(r *http.Request) deviceInfo {
+
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return deviceInfo{
+		UserAgent: userAgent,
+		IPAddress: ip,
+	}
+}
+
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	SignedInAt time.Time `json:"signed_in_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (cfg *apiConfig) handlerGetSessions(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	rows, err := cfg.dbQueries.GetActiveSessions(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't get sessions", err)
+		return
+	}
+
+	// Each family has at most one live token, the one issued by the most
+	// recent login or refresh, so its details describe the session
+	sessions := make([]Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, Session{
+			ID:         row.FamilyID,
+			UserAgent:  row.UserAgent,
+			IPAddress:  row.IpAddress,
+			SignedInAt: row.SignedInAt,
+			LastUsedAt: row.CreatedAt,
+			ExpiresAt:  row.ExpiresAt,
+		})
+	}
+
+	sendBack := struct {
+		Sessions []Session `json:"sessions"`
+	}{
+		sessions,
+	}
+
+	respondWithJSON(w, 200, sendBack)
+}
+
+func (cfg *apiConfig) handlerRevokeSession(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	sessionID, err := uuid.Parse(r.PathValue("sessionID"))
+	if err != nil {
+		respondWithError(w, 404, "Session not found", err)
+		return
+	}
+
+	sendData := database.RevokeUserSessionParams{
+		FamilyID: sessionID,
+		UserID:   userID,
+	}
+
+	// Scoped to the caller, so other users' sessions look the same as
+	// ones that don't exist
+	revoked, err := cfg.dbQueries.RevokeUserSession(r.Context(), sendData)
+	if err != nil {
+		respondWithError(w, 500, "Couldn't revoke session", err)
+		return
+	}
+
+	if revoked == 0 {
+		respondWithError(w, 404, "Session not found", nil)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (cfg *apiConfig) handlerRevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+
+	tokenString, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	userID, err := auth.ValidateJWT(tokenString, cfg.jwtKeys)
+	if err != nil {
+		respondWithAuthError(w, err)
+		return
+	}
+
+	if err := cfg.dbQueries.RevokeUserRefreshTokens(r.Context(), userID); err != nil {
+		respondWithError(w, 500, "Couldn't revoke sessions", err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
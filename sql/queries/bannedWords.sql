@@ -0,0 +1,20 @@
+-- name: GetBannedWords :many
+SELECT *
+FROM banned_words
+ORDER BY word;
+
+-- name: UpsertBannedWord :exec
+INSERT INTO banned_words (word, action, created_at, updated_at)
+VALUES (
+    $1,
+    $2,
+    NOW(),
+    NOW()
+)
+ON CONFLICT (word) DO UPDATE
+SET action = EXCLUDED.action,
+    updated_at = NOW();
+
+-- name: DeleteBannedWord :exec
+DELETE FROM banned_words
+WHERE word = $1;
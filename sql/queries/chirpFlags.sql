@@ -0,0 +1,13 @@
+-- name: CreateChirpFlag :exec
+INSERT INTO chirp_flags (chirp_id, word, created_at)
+VALUES (
+    $1,
+    $2,
+    NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: GetChirpFlags :many
+SELECT *
+FROM chirp_flags
+ORDER BY created_at DESC;
@@ -0,0 +1,26 @@
+-- name: CreateChirpMentions :exec
+INSERT INTO chirp_mentions (chirp_id, user_id, created_at)
+SELECT sqlc.arg(chirp_id)::uuid, id, NOW()
+FROM users
+WHERE LOWER(handle) = ANY(sqlc.arg(handles)::text[])
+  AND id <> sqlc.arg(author_id)::uuid
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteChirpMentions :exec
+DELETE FROM chirp_mentions
+WHERE chirp_id = sqlc.arg(chirp_id)::uuid
+  AND user_id NOT IN (
+    SELECT id FROM users WHERE LOWER(handle) = ANY(sqlc.arg(keep_handles)::text[])
+  );
+
+-- name: GetMentionsPage :many
+SELECT c.*
+FROM chirps c
+JOIN chirp_mentions m ON m.chirp_id = c.id
+WHERE m.user_id = sqlc.arg(user_id)
+  AND c.deleted_at IS NULL
+  AND c.hidden_at IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+   OR (c.created_at, c.id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit)::int;
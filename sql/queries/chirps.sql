@@ -1,10 +1,12 @@
 -- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_id, quoted_chirp_id)
 VALUES (
     gen_random_uuid(),  -- Generates a new UUID
     NOW(),              -- Sets created_at to the current timestamp
     NOW(),              -- Sets updated_at to the current timestamp
     $1,                 -- The body, passed in by the application
-    $2                  -- The user_id, passed in by the application
+    $2,                 -- The user_id, passed in by the application
+    $3,                 -- The chirp being replied to, if any
+    $4                  -- The chirp being quoted, if any
 )
 RETURNING *;
\ No newline at end of file
@@ -1,3 +1,14 @@
--- name: DeleteChirp :exec
+-- name: SoftDeleteChirp :exec
+UPDATE chirps
+SET deleted_at = NOW()
+WHERE id = $1;
+
+-- name: RestoreChirp :one
+UPDATE chirps
+SET deleted_at = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: PurgeDeletedChirps :execrows
 DELETE FROM chirps
-WHERE id = $1;
\ No newline at end of file
+WHERE deleted_at < NOW() - (sqlc.arg(retention_seconds)::bigint * INTERVAL '1 second');
\ No newline at end of file
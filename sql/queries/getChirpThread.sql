@@ -0,0 +1,23 @@
+-- name: GetChirpThread :many
+WITH RECURSIVE ancestors AS (
+    SELECT id, parent_id
+    FROM chirps
+    WHERE id = sqlc.arg(chirp_id)::uuid
+    UNION ALL
+    SELECT c.id, c.parent_id
+    FROM chirps c
+    JOIN ancestors a ON c.id = a.parent_id
+),
+thread AS (
+    SELECT c.id, c.created_at, c.updated_at, c.body, c.user_id, c.parent_id, c.quoted_chirp_id, c.deleted_at, c.hidden_at, 0 AS depth
+    FROM chirps c
+    WHERE c.id = (SELECT id FROM ancestors WHERE parent_id IS NULL)
+    UNION ALL
+    SELECT c.id, c.created_at, c.updated_at, c.body, c.user_id, c.parent_id, c.quoted_chirp_id, c.deleted_at, c.hidden_at, t.depth + 1
+    FROM chirps c
+    JOIN thread t ON c.parent_id = t.id
+)
+SELECT t.id, t.created_at, t.updated_at, t.body, t.user_id, t.parent_id, t.quoted_chirp_id, t.deleted_at, t.hidden_at, t.depth::int AS depth,
+       (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = t.id AND r.deleted_at IS NULL AND r.hidden_at IS NULL) AS reply_count
+FROM thread t
+ORDER BY t.depth, t.created_at, t.id;
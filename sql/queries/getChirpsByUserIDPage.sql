@@ -0,0 +1,37 @@
+-- name: GetChirpsByUserIDPageAsc :many
+SELECT c.*, activity.rechirped_at
+FROM (
+    SELECT id AS chirp_id, created_at AS listed_at, NULL::timestamp AS rechirped_at
+    FROM chirps
+    WHERE user_id = sqlc.arg(user_id)
+    UNION ALL
+    SELECT chirp_id, created_at, created_at
+    FROM rechirps
+    WHERE user_id = sqlc.arg(user_id)
+) activity
+JOIN chirps c ON c.id = activity.chirp_id
+WHERE c.deleted_at IS NULL
+  AND c.hidden_at IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+   OR (activity.listed_at, c.id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY activity.listed_at ASC, c.id ASC
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: GetChirpsByUserIDPageDesc :many
+SELECT c.*, activity.rechirped_at
+FROM (
+    SELECT id AS chirp_id, created_at AS listed_at, NULL::timestamp AS rechirped_at
+    FROM chirps
+    WHERE user_id = sqlc.arg(user_id)
+    UNION ALL
+    SELECT chirp_id, created_at, created_at
+    FROM rechirps
+    WHERE user_id = sqlc.arg(user_id)
+) activity
+JOIN chirps c ON c.id = activity.chirp_id
+WHERE c.deleted_at IS NULL
+  AND c.hidden_at IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+   OR (activity.listed_at, c.id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY activity.listed_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit)::int;
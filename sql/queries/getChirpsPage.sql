@@ -0,0 +1,19 @@
+-- name: GetChirpsPageAsc :many
+SELECT *
+FROM chirps
+WHERE deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+   OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: GetChirpsPageDesc :many
+SELECT *
+FROM chirps
+WHERE deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+   OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)::int;
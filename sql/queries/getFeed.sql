@@ -0,0 +1,11 @@
+-- name: GetFeedPage :many
+SELECT c.*
+FROM chirps c
+JOIN follows f ON f.followee_id = c.user_id
+WHERE f.follower_id = sqlc.arg(follower_id)
+  AND c.deleted_at IS NULL
+  AND c.hidden_at IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+   OR (c.created_at, c.id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit)::int;
@@ -1,4 +1,16 @@
 -- name: GetChirpByID :one
 SELECT *
 FROM chirps
-WHERE id=$1;
\ No newline at end of file
+WHERE id=$1 AND deleted_at IS NULL AND hidden_at IS NULL;
+
+-- name: GetChirpByIDForUser :one
+SELECT *
+FROM chirps
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL
+  AND (hidden_at IS NULL OR user_id = sqlc.arg(user_id));
+
+-- name: GetDeletedChirpByID :one
+SELECT *
+FROM chirps
+WHERE id=$1 AND deleted_at IS NOT NULL;
\ No newline at end of file
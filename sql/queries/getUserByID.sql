@@ -0,0 +1,4 @@
+-- name: GetUserByID :one
+SELECT *
+FROM users
+WHERE id=$1;
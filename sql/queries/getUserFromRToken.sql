@@ -1,4 +1,4 @@
 -- name: GetUserFromRToken :one
 SELECT *
 FROM refresh_tokens
-WHERE token=$1;
\ No newline at end of file
+WHERE token_hash=$1;
\ No newline at end of file
@@ -0,0 +1,59 @@
+-- name: CreateHashtags :exec
+INSERT INTO hashtags (id, tag, created_at)
+SELECT gen_random_uuid(), tag, NOW()
+FROM unnest(sqlc.arg(tags)::text[]) AS tag
+ON CONFLICT (tag) DO NOTHING;
+
+-- name: CreateChirpHashtags :exec
+INSERT INTO chirp_hashtags (chirp_id, hashtag_id, created_at)
+SELECT sqlc.arg(chirp_id)::uuid, id, NOW()
+FROM hashtags
+WHERE tag = ANY(sqlc.arg(tags)::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteChirpHashtags :exec
+DELETE FROM chirp_hashtags
+WHERE chirp_id = sqlc.arg(chirp_id)::uuid
+  AND hashtag_id NOT IN (
+    SELECT id FROM hashtags WHERE tag = ANY(sqlc.arg(keep_tags)::text[])
+  );
+
+-- name: GetChirpsByTagPageAsc :many
+SELECT c.*
+FROM chirps c
+JOIN chirp_hashtags ch ON ch.chirp_id = c.id
+JOIN hashtags h ON h.id = ch.hashtag_id
+WHERE h.tag = sqlc.arg(tag)
+  AND (sqlc.narg(user_id)::uuid IS NULL OR c.user_id = sqlc.narg(user_id)::uuid)
+  AND c.deleted_at IS NULL
+  AND c.hidden_at IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+   OR (c.created_at, c.id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY c.created_at ASC, c.id ASC
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: GetChirpsByTagPageDesc :many
+SELECT c.*
+FROM chirps c
+JOIN chirp_hashtags ch ON ch.chirp_id = c.id
+JOIN hashtags h ON h.id = ch.hashtag_id
+WHERE h.tag = sqlc.arg(tag)
+  AND (sqlc.narg(user_id)::uuid IS NULL OR c.user_id = sqlc.narg(user_id)::uuid)
+  AND c.deleted_at IS NULL
+  AND c.hidden_at IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+   OR (c.created_at, c.id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: GetTrendingHashtags :many
+SELECT h.tag, COUNT(*) AS chirp_count
+FROM chirp_hashtags ch
+JOIN hashtags h ON h.id = ch.hashtag_id
+JOIN chirps c ON c.id = ch.chirp_id
+WHERE ch.created_at > NOW() - (sqlc.arg(window_seconds)::bigint * INTERVAL '1 second')
+  AND c.deleted_at IS NULL
+  AND c.hidden_at IS NULL
+GROUP BY h.tag
+ORDER BY chirp_count DESC, h.tag ASC
+LIMIT sqlc.arg(page_limit)::int;
@@ -0,0 +1,9 @@
+-- name: HideChirp :execrows
+UPDATE chirps
+SET hidden_at = COALESCE(hidden_at, NOW())
+WHERE id = $1;
+
+-- name: UnhideChirp :execrows
+UPDATE chirps
+SET hidden_at = NULL
+WHERE id = $1;
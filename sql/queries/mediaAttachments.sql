@@ -0,0 +1,28 @@
+-- name: CreateMediaAttachment :one
+INSERT INTO media_attachments (id, user_id, content_type, size_bytes, width, height, storage_key, thumbnail_key, created_at)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    NOW()
+)
+RETURNING *;
+
+-- name: AttachMediaToChirp :execrows
+UPDATE media_attachments
+SET chirp_id = sqlc.arg(chirp_id)::uuid,
+    position = array_position(sqlc.arg(media_ids)::uuid[], id) - 1
+WHERE id = ANY(sqlc.arg(media_ids)::uuid[])
+  AND user_id = sqlc.arg(user_id)::uuid
+  AND chirp_id IS NULL;
+
+-- name: GetChirpsMedia :many
+SELECT *
+FROM media_attachments
+WHERE chirp_id = ANY(sqlc.arg(chirp_ids)::uuid[])
+ORDER BY chirp_id, position;
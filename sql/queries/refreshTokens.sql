@@ -1,10 +1,14 @@
 -- name: CreateRefeshToken :exec
-INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at, revoked_at)
+INSERT INTO refresh_tokens (token_hash, created_at, updated_at, user_id, expires_at, revoked_at, family_id, parent_token_hash, user_agent, ip_address)
 VALUES (
     $1,
     NOW(),
     NOW(),
     $2,
     $3,
-    null
+    null,
+    $4,
+    $5,
+    $6,
+    $7
 );
\ No newline at end of file
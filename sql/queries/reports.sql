@@ -0,0 +1,30 @@
+-- name: CreateReport :one
+INSERT INTO reports (id, chirp_id, reporter_id, reason, details, created_at, updated_at)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    $2,
+    $3,
+    $4,
+    NOW(),
+    NOW()
+)
+ON CONFLICT (chirp_id, reporter_id) WHERE status = 'open' DO NOTHING
+RETURNING *;
+
+-- name: GetReports :many
+SELECT *
+FROM reports
+WHERE sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CloseReport :one
+UPDATE reports
+SET status = sqlc.arg(status)::text,
+    resolved_at = NOW(),
+    resolved_by = sqlc.narg(resolved_by)::uuid,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)::uuid AND status = 'open'
+RETURNING *;
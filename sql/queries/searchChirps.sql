@@ -0,0 +1,10 @@
+-- name: SearchChirps :many
+SELECT id, created_at, updated_at, body, user_id, parent_id, quoted_chirp_id, ts_rank(search_vector, query)::real AS rank
+FROM chirps, websearch_to_tsquery('english', sqlc.arg(search_query)::text) AS query
+WHERE search_vector @@ query
+  AND deleted_at IS NULL
+  AND hidden_at IS NULL
+  AND (sqlc.narg(author_id)::uuid IS NULL OR user_id = sqlc.narg(author_id)::uuid)
+ORDER BY rank DESC, created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit)::int
+OFFSET sqlc.arg(page_offset)::int;
@@ -0,0 +1,18 @@
+-- name: GetActiveSessions :many
+SELECT t.family_id,
+       t.user_agent,
+       t.ip_address,
+       t.created_at,
+       t.expires_at,
+       (SELECT MIN(f.created_at) FROM refresh_tokens f WHERE f.family_id = t.family_id)::timestamp AS signed_in_at
+FROM refresh_tokens t
+WHERE t.user_id = $1
+  AND t.revoked_at IS NULL
+  AND t.expires_at > NOW()
+ORDER BY t.created_at DESC;
+
+-- name: RevokeUserSession :execrows
+UPDATE refresh_tokens
+SET updated_at = NOW(),
+    revoked_at = NOW()
+WHERE family_id = $1 AND user_id = $2 AND revoked_at IS NULL;
@@ -0,0 +1,12 @@
+-- name: UpdateChirpBody :one
+UPDATE chirps
+SET updated_at = NOW(),
+    body = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetChirpByIDForUpdate :one
+SELECT *
+FROM chirps
+WHERE id = $1 AND deleted_at IS NULL AND hidden_at IS NULL
+FOR UPDATE;
@@ -0,0 +1,14 @@
+-- name: UpdateUser :one
+UPDATE users
+SET email = COALESCE(sqlc.narg(email)::text, email),
+    hashed_password = COALESCE(sqlc.narg(hashed_password)::text, hashed_password),
+    display_name = COALESCE(sqlc.narg(display_name)::text, display_name),
+    bio = COALESCE(sqlc.narg(bio)::text, bio),
+    handle = COALESCE(sqlc.narg(handle)::text, handle),
+    email_verified_at = CASE
+        WHEN sqlc.narg(email)::text IS NOT NULL AND sqlc.narg(email)::text <> email THEN NULL
+        ELSE email_verified_at
+    END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)::uuid
+RETURNING *;
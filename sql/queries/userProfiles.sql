@@ -0,0 +1,10 @@
+-- name: GetUserProfiles :many
+SELECT id, handle, display_name, avatar_url
+FROM users
+WHERE id = ANY(sqlc.arg(user_ids)::uuid[]);
+
+-- name: UpdateUserAvatar :exec
+UPDATE users
+SET avatar_url = $2,
+    updated_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN quoted_chirp_id UUID REFERENCES chirps(id) ON DELETE SET NULL;
+
+CREATE TABLE rechirps (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, chirp_id)
+);
+
+CREATE INDEX rechirps_user_id_created_at_idx ON rechirps (user_id, created_at, chirp_id);
+
+-- +goose Down
+DROP TABLE rechirps;
+
+ALTER TABLE chirps
+DROP COLUMN quoted_chirp_id;
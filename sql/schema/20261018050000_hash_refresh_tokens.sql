@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE refresh_tokens
+RENAME COLUMN token TO token_hash;
+
+ALTER TABLE refresh_tokens
+RENAME COLUMN parent_token TO parent_token_hash;
+
+-- Hash the existing tokens in place so nobody is logged out. parent_token_hash
+-- follows through ON UPDATE CASCADE. This matches auth.HashToken.
+UPDATE refresh_tokens
+SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
+
+-- +goose Down
+-- Hashes can't be reversed, so every session has to log in again
+DELETE FROM refresh_tokens;
+
+ALTER TABLE refresh_tokens
+RENAME COLUMN parent_token_hash TO parent_token;
+
+ALTER TABLE refresh_tokens
+RENAME COLUMN token_hash TO token;